@@ -0,0 +1,76 @@
+package aql
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"github.com/kayx-org/freja/component"
+	"reflect"
+)
+
+// Querier runs AQL queries, it's implemented by driver.Database
+type Querier interface {
+	Query(ctx context.Context, query string, bindVars map[string]interface{}) (driver.Cursor, error)
+}
+
+// Execute runs the query and decodes all the results into result, that must be a pointer to a slice
+func Execute(ctx context.Context, db Querier, q *Query, result interface{}) error {
+	return execute(ctx, db, q, result, nil)
+}
+
+func execute(ctx context.Context, db Querier, q *Query, result interface{}, stats func(driver.Cursor)) error {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("result must be a pointer to a slice, got %T", result)
+	}
+
+	cursor, err := db.Query(ctx, q.String(), q.BindVars())
+	if err != nil {
+		return fmt.Errorf("unable to run query: %w", err)
+	}
+	defer cursor.Close()
+	if stats != nil {
+		stats(cursor)
+	}
+
+	items := v.Elem()
+	elemType := items.Type().Elem()
+	for cursor.HasMore() {
+		item := reflect.New(elemType)
+		if _, err := cursor.ReadDocument(ctx, item.Interface()); err != nil {
+			return fmt.Errorf("unable to read result: %w", err)
+		}
+		items = reflect.Append(items, item.Elem())
+	}
+	v.Elem().Set(items)
+
+	return nil
+}
+
+// ExecuteWithTotal runs the query like Execute and also returns the number of results without the last LIMIT
+func ExecuteWithTotal(ctx context.Context, db Querier, q *Query, result interface{}) (int64, error) {
+	var total int64
+	err := execute(driver.WithQueryFullCount(ctx), db, q, result, func(cursor driver.Cursor) {
+		total = cursor.Statistics().FullCount()
+	})
+
+	return total, err
+}
+
+// ExecuteOne runs the query and decodes the first result into result, if there are no results
+// component.ErrNotFound is returned
+func ExecuteOne(ctx context.Context, db Querier, q *Query, result interface{}) error {
+	cursor, err := db.Query(ctx, q.String(), q.BindVars())
+	if err != nil {
+		return fmt.Errorf("unable to run query: %w", err)
+	}
+	defer cursor.Close()
+
+	if _, err := cursor.ReadDocument(ctx, result); driver.IsNoMoreDocuments(err) {
+		return fmt.Errorf("query without results: %w", component.ErrNotFound)
+	} else if err != nil {
+		return fmt.Errorf("unable to read result: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+// Package aql builds AQL queries whose values are always passed as bind parameters,
+// the parameters are named by the query so they never collide
+package aql
+
+import (
+	"fmt"
+	"github.com/kayx-org/freja/component"
+	"strings"
+)
+
+type Direction string
+
+const (
+	Outbound Direction = "OUTBOUND"
+	Inbound  Direction = "INBOUND"
+	Any      Direction = "ANY"
+)
+
+// SortField is an expression of a SORT statement
+type SortField struct {
+	Expr string
+	Desc bool
+}
+
+// Asc sorts by the expression in ascending order
+func Asc(expr string) SortField {
+	return SortField{Expr: expr}
+}
+
+// Desc sorts by the expression in descending order
+func Desc(expr string) SortField {
+	return SortField{Expr: expr, Desc: true}
+}
+
+// Traversal is the definition of a graph traversal, either Graph or Edges must be set
+type Traversal struct {
+	// Vars are the names of the vertex, edge and path variables, only the first is required
+	Vars      []string
+	Min       int
+	Max       int
+	Direction Direction
+	// Start is the _id of the start vertex, or an expression if StartExpr is set
+	Start     string
+	StartExpr bool
+	Graph     string
+	Edges     []string
+}
+
+// Query is an AQL query built statement by statement. The expressions of the statements use ? as placeholder
+// of the values, that are replaced by bind parameters, a literal ? (like in the ternary operator) is written ??
+type Query struct {
+	statements []string
+	params     *params
+}
+
+type params struct {
+	vars  map[string]interface{}
+	count int
+}
+
+// New creates an empty query
+func New() *Query {
+	return &Query{params: &params{vars: make(map[string]interface{})}}
+}
+
+// Subquery creates a query that shares the bind parameters of q, to be used inside an expression of q
+func (q *Query) Subquery() *Query {
+	return &Query{params: q.params}
+}
+
+// Bind adds the value as a bind parameter and returns its reference
+func (q *Query) Bind(value interface{}) string {
+	return "@" + q.params.add("v", "", value)
+}
+
+// BindCollection adds the collection name as a bind parameter and returns its reference
+func (q *Query) BindCollection(name string) string {
+	return "@" + q.params.add("c", "@", name)
+}
+
+// For iterates over the documents of the collection
+func (q *Query) For(variable, collection string) *Query {
+	return q.add(fmt.Sprintf("FOR %s IN %s", variable, q.BindCollection(collection)))
+}
+
+// ForExpr iterates over the result of the expression
+func (q *Query) ForExpr(variable, expr string, values ...interface{}) *Query {
+	return q.add(fmt.Sprintf("FOR %s IN %s", variable, q.expr(expr, values)))
+}
+
+// ForSubquery iterates over the result of the subquery, that must have been created with Subquery
+func (q *Query) ForSubquery(variable string, sub *Query) *Query {
+	return q.add(fmt.Sprintf("FOR %s IN (%s)", variable, sub.String()))
+}
+
+// Traverse iterates over the vertices reached by the traversal
+func (q *Query) Traverse(t Traversal) *Query {
+	start := t.Start
+	if !t.StartExpr {
+		start = q.Bind(t.Start)
+	}
+	direction := t.Direction
+	if direction == "" {
+		direction = Outbound
+	}
+	stmt := fmt.Sprintf("FOR %s IN %d..%d %s %s", strings.Join(t.Vars, ", "), t.Min, t.Max, direction, start)
+
+	if t.Graph != "" {
+		return q.add(fmt.Sprintf("%s GRAPH %s", stmt, q.Bind(t.Graph)))
+	}
+	edges := make([]string, len(t.Edges))
+	for i, e := range t.Edges {
+		edges[i] = q.BindCollection(e)
+	}
+
+	return q.add(fmt.Sprintf("%s %s", stmt, strings.Join(edges, ", ")))
+}
+
+// Filter keeps the documents that match the expression
+func (q *Query) Filter(expr string, values ...interface{}) *Query {
+	return q.add("FILTER " + q.expr(expr, values))
+}
+
+// Let assigns the result of the expression to the variable
+func (q *Query) Let(variable, expr string, values ...interface{}) *Query {
+	return q.add(fmt.Sprintf("LET %s = %s", variable, q.expr(expr, values)))
+}
+
+// Sort sorts the documents by the fields
+func (q *Query) Sort(fields ...SortField) *Query {
+	exprs := make([]string, len(fields))
+	for i, f := range fields {
+		exprs[i] = f.Expr
+		if f.Desc {
+			exprs[i] += " DESC"
+		}
+	}
+
+	return q.add("SORT " + strings.Join(exprs, ", "))
+}
+
+// Limit returns at most count documents
+func (q *Query) Limit(count int) *Query {
+	return q.add("LIMIT " + q.Bind(count))
+}
+
+// LimitOffset skips offset documents and returns at most count documents
+func (q *Query) LimitOffset(offset, count int) *Query {
+	return q.add(fmt.Sprintf("LIMIT %s, %s", q.Bind(offset), q.Bind(count)))
+}
+
+// Collect groups the documents, expr is everything after the COLLECT keyword
+func (q *Query) Collect(expr string, values ...interface{}) *Query {
+	return q.add("COLLECT " + q.expr(expr, values))
+}
+
+// Return ends the query returning the result of the expression
+func (q *Query) Return(expr string, values ...interface{}) *Query {
+	return q.add("RETURN " + q.expr(expr, values))
+}
+
+// Raw adds a statement not covered by the builder
+func (q *Query) Raw(stmt string, values ...interface{}) *Query {
+	return q.add(q.expr(stmt, values))
+}
+
+// Paginate filters the documents after (or before) the cursor of the pagination, sorts them by the keys and limits
+// them to the fetch size of the paginator, the documents read are turned into a page with Paginator.Page.
+// The cursors must have the values of the keys in the same order, when paginating backwards the documents
+// are sorted in the opposite direction and Paginator.Page reverses them
+func (q *Query) Paginate(paginator component.Paginator, keys []SortField, pagination component.Pagination) error {
+	if pagination.After != "" && pagination.Before != "" {
+		return fmt.Errorf("pagination can't have both after and before cursors")
+	}
+
+	backwards := pagination.Before != ""
+	if encoded := pagination.After + pagination.Before; encoded != "" {
+		cursor, err := paginator.Cursors().Decode(encoded)
+		if err != nil {
+			return err
+		}
+		if len(cursor.Values) != len(keys) {
+			return fmt.Errorf("%w: expected %d values, got %d", component.ErrInvalidCursor, len(keys), len(cursor.Values))
+		}
+		q.add("FILTER " + q.keysetFilter(keys, cursor.Values, backwards))
+	}
+
+	sort := make([]SortField, len(keys))
+	for i, k := range keys {
+		sort[i] = SortField{Expr: k.Expr, Desc: k.Desc != backwards}
+	}
+	q.Sort(sort...)
+	q.Limit(paginator.FetchSize(pagination))
+
+	return nil
+}
+
+// keysetFilter returns the expression that matches the documents after the values in the order of the keys,
+// (k1 > v1) || (k1 == v1 && k2 > v2) || ... with < for the descending keys, or the opposite going backwards
+func (q *Query) keysetFilter(keys []SortField, values []interface{}, backwards bool) string {
+	refs := make([]string, len(values))
+	for i, v := range values {
+		refs[i] = q.Bind(v)
+	}
+
+	conditions := make([]string, len(keys))
+	for i, k := range keys {
+		op := ">"
+		if k.Desc != backwards {
+			op = "<"
+		}
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s == %s", keys[j].Expr, refs[j]))
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %s", k.Expr, op, refs[i]))
+		conditions[i] = "(" + strings.Join(terms, " && ") + ")"
+	}
+
+	return strings.Join(conditions, " || ")
+}
+
+// String returns the AQL of the query
+func (q *Query) String() string {
+	return strings.Join(q.statements, "\n")
+}
+
+// BindVars returns the bind parameters of the query and its subqueries
+func (q *Query) BindVars() map[string]interface{} {
+	return q.params.vars
+}
+
+func (q *Query) add(stmt string) *Query {
+	q.statements = append(q.statements, stmt)
+	return q
+}
+
+// expr replaces the placeholders of the expression by bind parameters of the values, the subqueries are inlined.
+// If the number of placeholders and values differs the query fails when it's run
+func (q *Query) expr(expr string, values []interface{}) string {
+	var b strings.Builder
+	next := 0
+	for i := 0; i < len(expr); i++ {
+		if expr[i] != '?' {
+			b.WriteByte(expr[i])
+			continue
+		}
+		if i+1 < len(expr) && expr[i+1] == '?' {
+			b.WriteByte('?')
+			i++
+			continue
+		}
+		if next >= len(values) {
+			b.WriteByte('?')
+			continue
+		}
+
+		if sub, ok := values[next].(*Query); ok {
+			b.WriteString("(" + sub.String() + ")")
+		} else {
+			b.WriteString(q.Bind(values[next]))
+		}
+		next++
+	}
+
+	return b.String()
+}
+
+// add stores the value and returns the name of the parameter, collection parameters are prefixed with @
+func (p *params) add(kind, prefix string, value interface{}) string {
+	name := fmt.Sprintf("%s%s%d", prefix, kind, p.count)
+	p.count++
+	p.vars[name] = value
+
+	return name
+}
@@ -0,0 +1,143 @@
+package aql
+
+import (
+	"github.com/kayx-org/freja/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	testCases := map[string]struct {
+		build            func() *Query
+		expectedQuery    string
+		expectedBindVars map[string]interface{}
+	}{
+		"the values of the statements should be bind parameters": {
+			build: func() *Query {
+				return New().For("u", "users").
+					Filter("u.email == ? && u.age >= ?", "a@b.c", 18).
+					Sort(Asc("u.name")).
+					LimitOffset(10, 5).
+					Return("u")
+			},
+			expectedQuery: "FOR u IN @@c0\nFILTER u.email == @v1 && u.age >= @v2\nSORT u.name\nLIMIT @v3, @v4\nRETURN u",
+			expectedBindVars: map[string]interface{}{
+				"@c0": "users", "v1": "a@b.c", "v2": 18, "v3": 10, "v4": 5,
+			},
+		},
+		"a double question mark should be a literal question mark": {
+			build: func() *Query {
+				return New().Return("? ?? 1 : 2", true)
+			},
+			expectedQuery:    "RETURN @v0 ? 1 : 2",
+			expectedBindVars: map[string]interface{}{"v0": true},
+		},
+		"the subqueries should share the bind parameters": {
+			build: func() *Query {
+				q := New()
+				sub := q.Subquery().For("o", "orders").Filter("o.user == u._key && o.total > ?", 10).Return("o")
+				return q.For("u", "users").Let("orders", "?", sub).Collect("city = u.city WITH COUNT INTO n").Return("{city, n}")
+			},
+			expectedQuery: "FOR u IN @@c2\nLET orders = (FOR o IN @@c0\nFILTER o.user == u._key && o.total > @v1\nRETURN o)\n" +
+				"COLLECT city = u.city WITH COUNT INTO n\nRETURN {city, n}",
+			expectedBindVars: map[string]interface{}{"@c0": "orders", "v1": 10, "@c2": "users"},
+		},
+		"the traversal of a graph should bind the start vertex and the graph": {
+			build: func() *Query {
+				return New().Traverse(Traversal{Vars: []string{"v", "e"}, Min: 1, Max: 2, Start: "users/1", Graph: "social"}).
+					Return("v")
+			},
+			expectedQuery:    "FOR v, e IN 1..2 OUTBOUND @v0 GRAPH @v1\nRETURN v",
+			expectedBindVars: map[string]interface{}{"v0": "users/1", "v1": "social"},
+		},
+		"the traversal of edge collections should bind the collections": {
+			build: func() *Query {
+				return New().For("u", "users").
+					Traverse(Traversal{Vars: []string{"f"}, Min: 1, Max: 1, Direction: Any, Start: "u", StartExpr: true, Edges: []string{"follows"}}).
+					Return("f")
+			},
+			expectedQuery:    "FOR u IN @@c0\nFOR f IN 1..1 ANY u @@c1\nRETURN f",
+			expectedBindVars: map[string]interface{}{"@c0": "users", "@c1": "follows"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			q := tc.build()
+			assert.Equal(t, tc.expectedQuery, q.String())
+			assert.Equal(t, tc.expectedBindVars, q.BindVars())
+		})
+	}
+}
+
+func TestQueryPaginate(t *testing.T) {
+	created := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	paginator := component.Paginator{Codec: component.NewCursorCodec([]byte("key")), Limits: component.DefaultPageLimits}
+	cursor, err := paginator.Codec.Encode(component.NewCursor(created, "10"))
+	assert.NoError(t, err)
+	unsigned, err := component.NewCursorCodec(nil).Encode(component.NewCursor(created, "10"))
+	assert.NoError(t, err)
+	keys := []SortField{Desc("u.createdAt"), Asc("u._key")}
+
+	testCases := map[string]struct {
+		paginator        *component.Paginator
+		pagination       component.Pagination
+		expectedQuery    string
+		expectedBindVars map[string]interface{}
+		expectedErr      string
+	}{
+		"without cursor it should only sort and limit": {
+			pagination:       component.Pagination{Limit: 20},
+			expectedQuery:    "FOR u IN @@c0\nSORT u.createdAt DESC, u._key\nLIMIT @v1\nRETURN u",
+			expectedBindVars: map[string]interface{}{"@c0": "users", "v1": 21},
+		},
+		"with an after cursor it should filter the next documents": {
+			pagination: component.Pagination{Limit: 20, After: cursor},
+			expectedQuery: "FOR u IN @@c0\nFILTER (u.createdAt < @v1) || (u.createdAt == @v1 && u._key > @v2)\n" +
+				"SORT u.createdAt DESC, u._key\nLIMIT @v3\nRETURN u",
+			expectedBindVars: map[string]interface{}{"@c0": "users", "v1": "2020-06-01T10:00:00Z", "v2": "10", "v3": 21},
+		},
+		"with a before cursor it should filter the previous documents in the opposite order": {
+			pagination: component.Pagination{Limit: 20, Before: cursor},
+			expectedQuery: "FOR u IN @@c0\nFILTER (u.createdAt > @v1) || (u.createdAt == @v1 && u._key < @v2)\n" +
+				"SORT u.createdAt, u._key DESC\nLIMIT @v3\nRETURN u",
+			expectedBindVars: map[string]interface{}{"@c0": "users", "v1": "2020-06-01T10:00:00Z", "v2": "10", "v3": 21},
+		},
+		"with a zero paginator it should use the default limits and unsigned cursors": {
+			paginator:  &component.Paginator{},
+			pagination: component.Pagination{After: unsigned},
+			expectedQuery: "FOR u IN @@c0\nFILTER (u.createdAt < @v1) || (u.createdAt == @v1 && u._key > @v2)\n" +
+				"SORT u.createdAt DESC, u._key\nLIMIT @v3\nRETURN u",
+			expectedBindVars: map[string]interface{}{"@c0": "users", "v1": "2020-06-01T10:00:00Z", "v2": "10", "v3": 21},
+		},
+		"with a forged cursor it should fail": {
+			pagination:  component.Pagination{After: cursor[:len(cursor)-2]},
+			expectedErr: "invalid cursor: wrong signature",
+		},
+		"with both cursors it should fail": {
+			pagination:  component.Pagination{After: cursor, Before: cursor},
+			expectedErr: "pagination can't have both after and before cursors",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := paginator
+			if tc.paginator != nil {
+				p = *tc.paginator
+			}
+			q := New().For("u", "users")
+			err := q.Paginate(p, keys, tc.pagination)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			q.Return("u")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedQuery, q.String())
+			assert.Equal(t, tc.expectedBindVars, q.BindVars())
+		})
+	}
+}
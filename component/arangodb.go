@@ -2,13 +2,20 @@ package component
 
 import (
 	"context"
-	"encoding/base64"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/cluster"
 	"github.com/arangodb/go-driver/http"
+	"github.com/arangodb/go-driver/vst"
+	"github.com/arangodb/go-driver/vst/protocol"
 	"github.com/kayx-org/freja/env"
-	"strings"
+	"net"
+	nethttp "net/http"
+	"net/url"
+	"path"
+	"sync"
 	"time"
 )
 
@@ -17,16 +24,22 @@ type IndexType int
 
 // For more info in regard of this error codes go to https://www.arangodb.com/docs/stable/appendix-error-codes.html
 const (
+	ErrConflict                     = 1200
+	ErrDocumentNotFound             = 1202
 	ErrDuplicate                    = 1207
+	ErrUniqueConstraintViolated     = 1210
 	ErrGraphDuplicate               = 1925
 	ErrCollectionAlreadyInGraph     = 1938
 	ErrCollectionAlreadyInEdgeGraph = 1929
 	ErrEdgeAlreadyInGraph           = 1920
 )
 
+// Pagination selects a page of a sorted list, After and Before are cursors of the documents the page
+// starts after or ends before, only one of them can be set
 type Pagination struct {
-	Limit int
-	After string
+	Limit  int
+	After  string
+	Before string
 }
 
 type Index interface {
@@ -91,14 +104,95 @@ func (g HashIndex) Fields() []string {
 	return g.IxFields
 }
 
+type PersistentIndex struct {
+	IxName   string
+	IxFields []string
+	Unique   bool
+	Sparse   bool
+}
+
+func (g PersistentIndex) Name() string {
+	return g.IxName
+}
+
+func (g PersistentIndex) Fields() []string {
+	return g.IxFields
+}
+
+type SkipListIndex struct {
+	IxName   string
+	IxFields []string
+	Unique   bool
+	Sparse   bool
+}
+
+func (g SkipListIndex) Name() string {
+	return g.IxName
+}
+
+func (g SkipListIndex) Fields() []string {
+	return g.IxFields
+}
+
+// ZKDIndex is a multi-dimensional index for numeric fields, it requires ArangoDB 3.9 or up
+type ZKDIndex struct {
+	IxName   string
+	IxFields []string
+	Unique   bool
+}
+
+func (g ZKDIndex) Name() string {
+	return g.IxName
+}
+
+func (g ZKDIndex) Fields() []string {
+	return g.IxFields
+}
+
+// InvertedIndex is an index used for searches with ArangoSearch, it requires ArangoDB 3.10 or up
+type InvertedIndex struct {
+	IxName   string
+	IxFields []string
+	Analyzer string
+}
+
+func (g InvertedIndex) Name() string {
+	return g.IxName
+}
+
+func (g InvertedIndex) Fields() []string {
+	return g.IxFields
+}
+
+// CollectionSchema is a JSON schema used to validate the documents, it requires ArangoDB 3.7 or up.
+// Level can be none, new, moderate or strict
+type CollectionSchema struct {
+	Rule    map[string]interface{} `json:"rule"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// CollectionOptions are applied when the collection is created, shards, replication and write concern
+// are only taken into account in a cluster
+type CollectionOptions struct {
+	NumberOfShards    int
+	ShardKeys         []string
+	ReplicationFactor int
+	WriteConcern      int
+	KeyOptions        *driver.CollectionKeyOptions
+	Schema            *CollectionSchema
+}
+
 type Collection struct {
 	Name    string
 	Indexes []Index
+	Options *CollectionOptions
 }
 
 type Edge struct {
 	Name    string
 	Indexes []Index
+	Options *CollectionOptions
 	// From: contains the names of one or more vertex collections that can contain source vertices.
 	// To: contains the names of one or more edge collections that can contain target vertices
 	From []string
@@ -109,16 +203,97 @@ type Graph struct {
 	Name     string
 	Edges    []Edge
 	Vertexes []Collection
+	Options  *GraphOptions
+}
+
+// GraphOptions are applied when the graph is created, shards, replication and write concern
+// are only taken into account in a cluster
+type GraphOptions struct {
+	NumberOfShards    int
+	ReplicationFactor int
+	WriteConcern      int
+	// IsSmart creates a smart graph sharded by SmartGraphAttribute, it requires the Enterprise Edition
+	IsSmart             bool
+	SmartGraphAttribute string
+	// Satellite creates a graph replicated to every DB-Server, it requires the Enterprise Edition 3.7 or up
+	Satellite bool
+	// OrphanCollections are vertex collections not used in any edge definition
+	OrphanCollections []string
 }
 
 type Arango struct {
-	db        string
-	user      string
-	password  string
-	endpoints []string
-	clientDB  driver.Database
-	graph     driver.Graph
-	client    driver.Client
+	db              string
+	user            string
+	password        string
+	endpoints       []string
+	clientDB        driver.Database
+	graph           driver.Graph
+	client          driver.Client
+	transport       *nethttp.Transport
+	endpointClients map[string]driver.Client
+	cursors         *CursorCodec
+	limits          PageLimits
+	tlsConfig       *tls.Config
+	jwt             bool
+	vst             bool
+	http2           bool
+	timeout         time.Duration
+	syncInterval    time.Duration
+	stopSync        chan struct{}
+	skipCreate      bool
+	collections     map[string]driver.Collection
+	mutex           sync.RWMutex
+}
+
+// OptionArangoTLS sets the TLS configuration of the connections, the endpoints must use https or ssl
+func OptionArangoTLS(config *tls.Config) OptionArango {
+	return func(a *Arango) {
+		a.tlsConfig = config
+	}
+}
+
+// OptionArangoJWT authenticates with a JWT token obtained with the user and password instead of basic auth
+func OptionArangoJWT() OptionArango {
+	return func(a *Arango) {
+		a.jwt = true
+	}
+}
+
+// OptionArangoVST connects using the VelocyStream protocol instead of HTTP
+func OptionArangoVST() OptionArango {
+	return func(a *Arango) {
+		a.vst = true
+	}
+}
+
+// OptionArangoHTTP2 connects using HTTP/2, it's only used with TLS
+func OptionArangoHTTP2() OptionArango {
+	return func(a *Arango) {
+		a.http2 = true
+	}
+}
+
+// OptionArangoTimeout sets the timeout of the requests whose context has no deadline
+func OptionArangoTimeout(timeout time.Duration) OptionArango {
+	return func(a *Arango) {
+		a.timeout = timeout
+	}
+}
+
+// OptionArangoEndpointSync updates the endpoints with the coordinators of the cluster every interval,
+// so the requests fail over to the available coordinators
+func OptionArangoEndpointSync(interval time.Duration) OptionArango {
+	return func(a *Arango) {
+		a.syncInterval = interval
+	}
+}
+
+// OptionCursorKey signs the pagination cursors with the key so the clients can't forge them,
+// the cursors of the previous format are rejected unless Cursors().AllowLegacy() is used
+func OptionCursorKey(key []byte) OptionArango {
+	return func(a *Arango) {
+		a.cursors = NewCursorCodec(key)
+	}
 }
 
 func NewArango(db string, endpoints []string, user, password string, options ...OptionArango) *Arango {
@@ -127,6 +302,8 @@ func NewArango(db string, endpoints []string, user, password string, options ...
 		user:      user,
 		password:  password,
 		endpoints: endpoints,
+		cursors:   NewCursorCodec(nil),
+		limits:    DefaultPageLimits,
 	}
 	for _, o := range options {
 		o(a)
@@ -139,41 +316,269 @@ func (a *Arango) DB() driver.Database {
 	return a.clientDB
 }
 
+func (a *Arango) Client() driver.Client {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.client
+}
+
+// Cursors returns the codec of the pagination cursors
+func (a *Arango) Cursors() *CursorCodec {
+	return a.cursorCodec()
+}
+
+// Paginator returns the configuration of the pagination of the queries
+func (a *Arango) Paginator() Paginator {
+	limits := a.limits
+	if limits == (PageLimits{}) {
+		limits = DefaultPageLimits
+	}
+
+	return Paginator{Codec: a.cursorCodec(), Limits: limits}
+}
+
+func (a *Arango) Endpoints() []string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.endpoints
+}
+
 func (a *Arango) InitDB(ctx context.Context) error {
 	if a.clientDB != nil {
 		return nil
 	}
 
+	client, err := a.ensureClient()
+	if err != nil {
+		return err
+	}
+
+	if err := a.openDB(ctx, client); err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.syncInterval > 0 && a.stopSync == nil {
+		a.stopSync = make(chan struct{})
+		go a.syncEndpoints(client, a.stopSync)
+	}
+
+	return nil
+}
+
+// ensureClient returns the client, creating it if it's the first time or it was closed
+func (a *Arango) ensureClient() (driver.Client, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 	if a.client == nil {
-		conn, err := http.NewConnection(http.ConnectionConfig{
-			Endpoints: a.endpoints,
-			ConnLimit: env.GetEnvAsInt("ARANGODB_CONN_LIMIT", 32),
-		})
+		client, err := a.newClient(a.endpoints)
 		if err != nil {
-			return fmt.Errorf("unable to create connection: %w", err)
+			return nil, err
 		}
+		a.client = client
+	}
+
+	return a.client, nil
+}
+
+// syncEndpoints updates the endpoints of the client with the ones of the cluster until stop is closed,
+// a failed synchronisation keeps the previous endpoints until the next one
+func (a *Arango) syncEndpoints(client driver.Client, stop chan struct{}) {
+	ticker := time.NewTicker(a.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), a.syncInterval)
+			if err := client.SynchronizeEndpoints2(ctx, a.db); err == nil {
+				a.mutex.Lock()
+				a.endpoints = client.Connection().Endpoints()
+				a.mutex.Unlock()
+			}
+			cancel()
+		}
+	}
+}
+
+func (a *Arango) newClient(endpoints []string) (driver.Client, error) {
+	conn, err := a.newConnection(endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection: %w", err)
+	}
+
+	auth := driver.BasicAuthentication(a.user, a.password)
+	if a.jwt {
+		auth = driver.JWTAuthentication(a.user, a.password)
+	}
+	client, err := driver.NewClient(driver.ClientConfig{
+		Connection:     conn,
+		Authentication: auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect: %w", err)
+	}
+
+	return client, nil
+}
 
-		a.client, err = driver.NewClient(driver.ClientConfig{
-			Connection:     conn,
-			Authentication: driver.BasicAuthentication(a.user, a.password),
+func (a *Arango) newConnection(endpoints []string) (driver.Connection, error) {
+	connLimit := env.GetEnvAsInt("ARANGODB_CONN_LIMIT", 32)
+	if a.vst {
+		return vst.NewConnection(vst.ConnectionConfig{
+			Endpoints: endpoints,
+			TLSConfig: a.tlsConfig,
+			Transport: protocol.TransportConfig{
+				ConnLimit: connLimit,
+				Version:   protocol.Version1_1,
+			},
+			ConnectionConfig: cluster.ConnectionConfig{DefaultTimeout: a.timeout},
 		})
+	}
+
+	if a.transport == nil {
+		a.transport = &nethttp.Transport{
+			Proxy:                 nethttp.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+			MaxIdleConns:          100,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     a.http2,
+		}
+	}
+
+	return http.NewConnection(http.ConnectionConfig{
+		Endpoints:        endpoints,
+		ConnLimit:        connLimit,
+		Transport:        a.transport,
+		TLSConfig:        a.tlsConfig,
+		ConnectionConfig: cluster.ConnectionConfig{DefaultTimeout: a.timeout},
+	})
+}
+
+// Version returns the version of the server the client is connected to
+func (a *Arango) Version(ctx context.Context) (driver.VersionInfo, error) {
+	client := a.Client()
+	if client == nil {
+		return driver.VersionInfo{}, errors.New("client not initialized")
+	}
+
+	return client.Version(ctx)
+}
+
+// ClusterHealth returns the health of every server in the cluster, if it's a single server
+// an error is returned that can be checked with driver.IsPreconditionFailed
+func (a *Arango) ClusterHealth(ctx context.Context) (driver.ClusterHealth, error) {
+	client := a.Client()
+	if client == nil {
+		return driver.ClusterHealth{}, errors.New("client not initialized")
+	}
+
+	cluster, err := client.Cluster(ctx)
+	if err != nil {
+		return driver.ClusterHealth{}, err
+	}
+
+	return cluster.Health(ctx)
+}
+
+// PingEndpoints checks each one of the configured endpoints on its own, returning the result per endpoint
+func (a *Arango) PingEndpoints(ctx context.Context) map[string]error {
+	endpoints := a.Endpoints()
+	res := make(map[string]error, len(endpoints))
+	for _, ep := range endpoints {
+		client, err := a.endpointClient(ep)
 		if err != nil {
-			return fmt.Errorf("unable to connect: %w", err)
+			res[ep] = err
+			continue
 		}
+
+		_, err = client.Version(ctx)
+		res[ep] = err
 	}
 
-	return a.createDB(ctx)
+	return res
 }
 
-func (a *Arango) createDB(ctx context.Context) error {
+// endpointClient returns the client connected only to the endpoint, they are kept until Close
+func (a *Arango) endpointClient(endpoint string) (driver.Client, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if client, ok := a.endpointClients[endpoint]; ok {
+		return client, nil
+	}
+
+	client, err := a.newClient([]string{endpoint})
+	if err != nil {
+		return nil, err
+	}
+	if a.endpointClients == nil {
+		a.endpointClients = make(map[string]driver.Client)
+	}
+	a.endpointClients[endpoint] = client
+
+	return client, nil
+}
+
+// Close releases the connections, InitDB needs to be called again before using it. The health checks running
+// at the same time fail with the client not initialized
+func (a *Arango) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.stopSync != nil {
+		close(a.stopSync)
+		a.stopSync = nil
+	}
+	if a.transport != nil {
+		a.transport.CloseIdleConnections()
+	}
+
+	a.client = nil
+	a.clientDB = nil
+	a.graph = nil
+	a.endpointClients = nil
+	a.transport = nil
+	a.collections = nil
+
+	return nil
+}
+
+// databaseClient is the part of driver.Client used to open the database and to provision the users
+type databaseClient interface {
+	Database(ctx context.Context, name string) (driver.Database, error)
+	CreateDatabase(ctx context.Context, name string, options *driver.CreateDatabaseOptions) (driver.Database, error)
+	UserExists(ctx context.Context, name string) (bool, error)
+	User(ctx context.Context, name string) (driver.User, error)
+	CreateUser(ctx context.Context, name string, options *driver.UserOptions) (driver.User, error)
+}
+
+// openDB connects to the database, creating it with the user unless OptionArangoSkipCreate is used
+func (a *Arango) openDB(ctx context.Context, client databaseClient) error {
+	if !a.skipCreate {
+		return a.createDB(ctx, client)
+	}
+
+	db, err := client.Database(ctx, a.db)
+	if err != nil {
+		return fmt.Errorf("unable to get db: %w", err)
+	}
+	a.clientDB = db
+
+	return nil
+}
+
+func (a *Arango) createDB(ctx context.Context, client databaseClient) error {
 	true := true
-	connDB, err := a.client.CreateDatabase(ctx, a.db, &driver.CreateDatabaseOptions{
+	connDB, err := client.CreateDatabase(ctx, a.db, &driver.CreateDatabaseOptions{
 		Users: []driver.CreateDatabaseUserOptions{
 			{UserName: a.user, Password: a.password, Active: &true},
 		},
 		Options: driver.CreateDatabaseDefaultOptions{},
 	})
-	a.clientDB, err = a.processCreateDBError(ctx, connDB, err)
+	a.clientDB, err = a.processCreateDBError(ctx, client, connDB, err)
 	if err != nil {
 		return err
 	}
@@ -181,13 +586,13 @@ func (a *Arango) createDB(ctx context.Context) error {
 	return nil
 }
 
-func (a *Arango) processCreateDBError(ctx context.Context, dbClient driver.Database, err error) (driver.Database, error) {
+func (a *Arango) processCreateDBError(ctx context.Context, client databaseClient, dbClient driver.Database, err error) (driver.Database, error) {
 	if err == nil {
 		return dbClient, nil
 	}
 
 	if driver.IsArangoErrorWithErrorNum(err, ErrDuplicate) {
-		if c, err := a.client.Database(ctx, a.db); err != nil {
+		if c, err := client.Database(ctx, a.db); err != nil {
 			return nil, fmt.Errorf("unable to get db: %w", err)
 		} else {
 			return c, nil
@@ -197,15 +602,40 @@ func (a *Arango) processCreateDBError(ctx context.Context, dbClient driver.Datab
 	return nil, fmt.Errorf("unable to create db: %w", err)
 }
 
+// CreateGraph creates the graph with its vertexes, edges and orphan collections, or gets it if it already exists.
+// The edge definitions of an existing graph are compared against the declared ones and updated when they differ.
+// Without options the graph is created with the defaults of the server
 func (a *Arango) CreateGraph(ctx context.Context, graph *Graph) error {
-	g, err := a.clientDB.CreateGraph(ctx, graph.Name, &driver.CreateGraphOptions{ReplicationFactor: 2})
+	// collections with options are created first, otherwise the graph would create them with its own
+	for _, v := range graph.Vertexes {
+		if v.Options != nil {
+			if _, err := a.createCollection(ctx, v.Name, driver.CollectionTypeDocument, v.Options); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range graph.Edges {
+		if e.Options != nil {
+			if _, err := a.createCollection(ctx, e.Name, driver.CollectionTypeEdge, e.Options); err != nil {
+				return err
+			}
+		}
+	}
+
+	g, err := a.createGraph(ctx, graph)
 	g, err = a.processCreateGraphError(ctx, g, graph.Name, err)
 	if err != nil {
 		return err
 	}
 	a.graph = g
 
-	for _, v := range graph.Vertexes {
+	vertexes := graph.Vertexes
+	if graph.Options != nil {
+		for _, name := range graph.Options.OrphanCollections {
+			vertexes = append(vertexes, Collection{Name: name})
+		}
+	}
+	for _, v := range vertexes {
 		c, err := g.CreateVertexCollection(ctx, v.Name)
 		c, err = a.processCollectionGraphError(ctx, c, v.Name, err)
 		if err != nil {
@@ -216,11 +646,17 @@ func (a *Arango) CreateGraph(ctx context.Context, graph *Graph) error {
 		}
 	}
 
+	existing, err := a.edgeDefinitions(ctx, g)
+	if err != nil {
+		return err
+	}
+
 	for _, e := range graph.Edges {
-		c, err := g.CreateEdgeCollection(ctx, e.Name, driver.VertexConstraints{
+		constraints := driver.VertexConstraints{
 			From: e.From,
 			To:   e.To,
-		})
+		}
+		c, err := g.CreateEdgeCollection(ctx, e.Name, constraints)
 		c, err = a.processEdgeCollectionGraphError(ctx, c, e.Name, err)
 		if err != nil {
 			return fmt.Errorf("unable to create edge collection '%s', :%w", e.Name, err)
@@ -228,10 +664,11 @@ func (a *Arango) CreateGraph(ctx context.Context, graph *Graph) error {
 		if err := a.ensureIndexes(ctx, c, e.Indexes); err != nil {
 			return fmt.Errorf("unable to ensure indexes: %w", err)
 		}
-		if err := g.SetVertexConstraints(ctx, e.Name, driver.VertexConstraints{
-			From: e.From,
-			To:   e.To,
-		}); err != nil {
+
+		if current, ok := existing[e.Name]; ok && sameVertexConstraints(current, constraints) {
+			continue
+		}
+		if err := g.SetVertexConstraints(ctx, e.Name, constraints); err != nil {
 			return fmt.Errorf("unable to set vertex constrainse for edge '%s', :%w", e.Name, err)
 		}
 	}
@@ -239,11 +676,86 @@ func (a *Arango) CreateGraph(ctx context.Context, graph *Graph) error {
 	return nil
 }
 
+func (a *Arango) createGraph(ctx context.Context, graph *Graph) (driver.Graph, error) {
+	definitions := make([]driver.EdgeDefinition, 0, len(graph.Edges))
+	for _, e := range graph.Edges {
+		definitions = append(definitions, driver.EdgeDefinition{Collection: e.Name, From: e.From, To: e.To})
+	}
+
+	opts := GraphOptions{}
+	if graph.Options != nil {
+		opts = *graph.Options
+	}
+
+	if opts.Satellite {
+		// the driver only takes a numeric replication factor, satellite graphs are created through the API
+		if err := a.rawRequest(ctx, "POST", path.Join("_api", "gharial"), nil, map[string]interface{}{
+			"name":              graph.Name,
+			"edgeDefinitions":   definitions,
+			"orphanCollections": opts.OrphanCollections,
+			"options":           map[string]interface{}{"replicationFactor": "satellite"},
+		}, nil, 201, 202); err != nil {
+			return nil, err
+		}
+
+		return a.clientDB.Graph(ctx, graph.Name)
+	}
+
+	return a.clientDB.CreateGraph(ctx, graph.Name, &driver.CreateGraphOptions{
+		OrphanVertexCollections: opts.OrphanCollections,
+		EdgeDefinitions:         definitions,
+		IsSmart:                 opts.IsSmart,
+		SmartGraphAttribute:     opts.SmartGraphAttribute,
+		NumberOfShards:          opts.NumberOfShards,
+		ReplicationFactor:       opts.ReplicationFactor,
+		WriteConcern:            opts.WriteConcern,
+	})
+}
+
+// edgeDefinitions returns the vertex constraints of every edge collection of the graph
+func (a *Arango) edgeDefinitions(ctx context.Context, g driver.Graph) (map[string]driver.VertexConstraints, error) {
+	cols, constraints, err := g.EdgeCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get edge collections of graph '%s': %w", g.Name(), err)
+	}
+
+	definitions := make(map[string]driver.VertexConstraints, len(cols))
+	for i, c := range cols {
+		definitions[c.Name()] = constraints[i]
+	}
+
+	return definitions, nil
+}
+
+func sameVertexConstraints(a, b driver.VertexConstraints) bool {
+	return sameNames(a.From, b.From) && sameNames(a.To, b.To)
+}
+
+// sameNames compares two lists of collection names regardless of their order
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	names := make(map[string]int, len(a))
+	for _, n := range a {
+		names[n]++
+	}
+	for _, n := range b {
+		if names[n] == 0 {
+			return false
+		}
+		names[n]--
+	}
+
+	return true
+}
+
 func (a *Arango) CreateCollections(ctx context.Context, collections []Collection) error {
 	for _, col := range collections {
-		c, err := a.clientDB.CreateCollection(ctx, col.Name, &driver.CreateCollectionOptions{})
+		c, err := a.createCollection(ctx, col.Name, driver.CollectionTypeDocument, col.Options)
 		if err != nil {
-			return fmt.Errorf("unable to create collection '%s', :%w", c.Name(), err)
+			return err
 		}
 		if err := a.ensureIndexes(ctx, c, col.Indexes); err != nil {
 			return err
@@ -253,6 +765,69 @@ func (a *Arango) CreateCollections(ctx context.Context, collections []Collection
 	return nil
 }
 
+// createCollection creates the collection with the given options, or gets it if it already exists,
+// and sets its schema
+func (a *Arango) createCollection(ctx context.Context, name string, colType driver.CollectionType, options *CollectionOptions) (driver.Collection, error) {
+	opts := &driver.CreateCollectionOptions{Type: colType}
+	if options != nil {
+		opts.NumberOfShards = options.NumberOfShards
+		opts.ShardKeys = options.ShardKeys
+		opts.ReplicationFactor = options.ReplicationFactor
+		opts.WriteConcern = options.WriteConcern
+		opts.KeyOptions = options.KeyOptions
+	}
+
+	c, err := a.clientDB.CreateCollection(ctx, name, opts)
+	c, err = a.processCreateCollectionError(ctx, c, name, err)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create collection '%s', :%w", name, err)
+	}
+
+	if options != nil && options.Schema != nil {
+		if err := a.rawRequest(ctx, "PUT", path.Join("_api", "collection", url.PathEscape(name), "properties"), nil,
+			map[string]interface{}{"schema": options.Schema}, nil, 200); err != nil {
+			return nil, fmt.Errorf("unable to set schema of collection '%s': %w", name, err)
+		}
+	}
+
+	return c, nil
+}
+
+// rawRequest sends a request to the API of the database, it's meant for the features the driver doesn't support yet
+func (a *Arango) rawRequest(ctx context.Context, method, apiPath string, query map[string]string, body, result interface{}, statuses ...int) error {
+	client := a.Client()
+	if client == nil {
+		return errors.New("client not initialized")
+	}
+
+	conn := client.Connection()
+	req, err := conn.NewRequest(method, path.Join("_db", url.PathEscape(a.db), apiPath))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	for k, v := range query {
+		req.SetQuery(k, v)
+	}
+	if body != nil {
+		if _, err := req.SetBody(body); err != nil {
+			return fmt.Errorf("unable to set body: %w", err)
+		}
+	}
+
+	res, err := conn.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := res.CheckStatus(statuses...); err != nil {
+		return err
+	}
+	if result != nil {
+		return res.ParseBody("", result)
+	}
+
+	return nil
+}
+
 func (a *Arango) ensureIndexes(ctx context.Context, c driver.Collection, indexes []Index) error {
 	for _, ix := range indexes {
 		switch index := ix.(type) {
@@ -279,6 +854,51 @@ func (a *Arango) ensureIndexes(ctx context.Context, c driver.Collection, indexes
 			if err != nil {
 				return fmt.Errorf("unable to create geo index '%s': %w", ix.Name(), err)
 			}
+		case PersistentIndex:
+			_, _, err := c.EnsurePersistentIndex(ctx, ix.Fields(), &driver.EnsurePersistentIndexOptions{
+				Unique:       index.Unique,
+				Sparse:       index.Sparse,
+				InBackground: true,
+				Name:         ix.Name(),
+			})
+			if err != nil {
+				return fmt.Errorf("unable to create persistent index '%s': %w", ix.Name(), err)
+			}
+		case SkipListIndex:
+			_, _, err := c.EnsureSkipListIndex(ctx, ix.Fields(), &driver.EnsureSkipListIndexOptions{
+				Unique:        index.Unique,
+				Sparse:        index.Sparse,
+				NoDeduplicate: false,
+				InBackground:  true,
+				Name:          ix.Name(),
+			})
+			if err != nil {
+				return fmt.Errorf("unable to create skiplist index '%s': %w", ix.Name(), err)
+			}
+		case ZKDIndex:
+			if err := a.ensureRawIndex(ctx, c.Name(), map[string]interface{}{
+				"type":            "zkd",
+				"name":            ix.Name(),
+				"fields":          ix.Fields(),
+				"fieldValueTypes": "double",
+				"unique":          index.Unique,
+				"inBackground":    true,
+			}); err != nil {
+				return fmt.Errorf("unable to create zkd index '%s': %w", ix.Name(), err)
+			}
+		case InvertedIndex:
+			body := map[string]interface{}{
+				"type":         "inverted",
+				"name":         ix.Name(),
+				"fields":       ix.Fields(),
+				"inBackground": true,
+			}
+			if index.Analyzer != "" {
+				body["analyzer"] = index.Analyzer
+			}
+			if err := a.ensureRawIndex(ctx, c.Name(), body); err != nil {
+				return fmt.Errorf("unable to create inverted index '%s': %w", ix.Name(), err)
+			}
 		case TTLIndex:
 			_, _, err := c.EnsureTTLIndex(ctx, index.IxField, index.ExpireAfter, &driver.EnsureTTLIndexOptions{
 				InBackground: true,
@@ -304,6 +924,27 @@ func (a *Arango) ensureIndexes(ctx context.Context, c driver.Collection, indexes
 	return nil
 }
 
+// ensureRawIndex creates the indexes the driver doesn't support, the server answers 200 if it already exists
+func (a *Arango) ensureRawIndex(ctx context.Context, collection string, body map[string]interface{}) error {
+	return a.rawRequest(ctx, "POST", path.Join("_api", "index"), map[string]string{"collection": collection}, body, nil, 200, 201)
+}
+
+func (a *Arango) processCreateCollectionError(ctx context.Context, collection driver.Collection, name string, err error) (driver.Collection, error) {
+	if err == nil {
+		return collection, nil
+	}
+
+	if driver.IsArangoErrorWithErrorNum(err, ErrDuplicate) {
+		if c, err := a.clientDB.Collection(ctx, name); err != nil {
+			return nil, fmt.Errorf("unable to get collection: %w", err)
+		} else {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unable to create collection: %w", err)
+}
+
 func (a *Arango) processCollectionGraphError(ctx context.Context, collection driver.Collection, name string, err error) (driver.Collection, error) {
 	if err == nil {
 		return collection, nil
@@ -353,12 +994,14 @@ func (a *Arango) processCreateGraphError(ctx context.Context, graph driver.Graph
 	return nil, fmt.Errorf("unable to create graph: %w", err)
 }
 
-// EnhanceBinVarsWithIdTimeCursor adds an extra set of bindVars used for pagination and adds a limit to the pagination
+// EnhanceBindVarsWithIdTimeCursor adds the bind variables of the pagination by creation time and id,
+// paginationLimit is one more than the page size so Paginator().Page can tell if there are more documents.
+// It only paginates forwards, the paginations with Before are rejected, aql.Query.Paginate supports both
 func (a *Arango) EnhanceBindVarsWithIdTimeCursor(bindVars map[string]interface{}, pagination Pagination) (map[string]interface{}, error) {
-	limit := 100
-	if pagination.Limit < 100 {
-		limit = pagination.Limit
+	if pagination.Before != "" {
+		return nil, errors.New("pagination with a before cursor is not supported")
 	}
+	limit := a.Paginator().FetchSize(pagination)
 
 	bindVars["paginationOn"] = false
 	bindVars["paginationAfterId"] = ""
@@ -378,28 +1021,37 @@ func (a *Arango) EnhanceBindVarsWithIdTimeCursor(bindVars map[string]interface{}
 	return bindVars, nil
 }
 
+// GetIDTimeCursor returns the id and the time of a cursor created with CreateCursorWithIdAndTime
 func (a *Arango) GetIDTimeCursor(cursor string) (string, time.Time, error) {
-	res, err := base64.StdEncoding.DecodeString(cursor)
+	c, err := a.cursorCodec().Decode(cursor)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("unable to decode cursor: %w", err)
+		return "", time.Time{}, err
 	}
 
-	cursorStr := string(res)
-	cursors := strings.Split(cursorStr, "*")
-	if len(cursors) != 2 {
-		return "", time.Time{}, errors.New("cursor must have two values")
+	created, err := c.Time(0)
+	if err != nil {
+		return "", time.Time{}, err
 	}
-
-	created, err := time.Parse(time.RFC3339, cursors[1])
+	id, err := c.String(1)
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("unabelt to parse cursor time: %w", err)
+		return "", time.Time{}, err
 	}
 
-	return cursors[0], created, nil
+	return id, created, nil
 }
 
 // CreateCursorWithIdAndTime gets a cursor using the Id and createdAt
 func (a *Arango) CreateCursorWithIdAndTime(id string, createdAt time.Time) string {
-	cursor := fmt.Sprintf("%s*%s", id, createdAt.Format(time.RFC3339))
-	return base64.StdEncoding.EncodeToString([]byte(cursor))
+	// the values are a string and a time so they can always be encoded
+	cursor, _ := a.cursorCodec().Encode(NewCursor(createdAt, id))
+	return cursor
+}
+
+// cursorCodec returns the codec of the cursors, Arango may have been created without NewArango
+func (a *Arango) cursorCodec() *CursorCodec {
+	if a.cursors == nil {
+		return NewCursorCodec(nil)
+	}
+
+	return a.cursors
 }
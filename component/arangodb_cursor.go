@@ -0,0 +1,168 @@
+package component
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const cursorVersion = "v2"
+
+// ErrInvalidCursor is returned when a cursor can't be decoded or its signature doesn't match
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of a document in a list sorted by one or more keys,
+// Values are the values of the sort keys of the document in the same order as the keys
+type Cursor struct {
+	Values []interface{}
+}
+
+// NewCursor creates a cursor with the values of the sort keys of a document, times are kept with nanoseconds
+func NewCursor(values ...interface{}) Cursor {
+	return Cursor{Values: values}
+}
+
+// Time returns the value i of the cursor as a time
+func (c Cursor) Time(i int) (time.Time, error) {
+	if i >= len(c.Values) {
+		return time.Time{}, fmt.Errorf("%w: missing value %d", ErrInvalidCursor, i)
+	}
+
+	switch v := c.Values[i].(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: unable to parse time: %s", ErrInvalidCursor, err)
+		}
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: value %d is not a time", ErrInvalidCursor, i)
+	}
+}
+
+// String returns the value i of the cursor as a string
+func (c Cursor) String(i int) (string, error) {
+	if i >= len(c.Values) {
+		return "", fmt.Errorf("%w: missing value %d", ErrInvalidCursor, i)
+	}
+	s, ok := c.Values[i].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: value %d is not a string", ErrInvalidCursor, i)
+	}
+
+	return s, nil
+}
+
+// CursorCodec encodes the cursors as opaque strings, when it has a key the cursors are signed with HMAC-SHA256
+// and the ones without a valid signature are rejected. The cursors created before the versioned format,
+// base64 of id*time, are decoded as a cursor with the time and the id, they can't be signed so a codec with a key
+// only accepts them if AllowLegacy is used
+type CursorCodec struct {
+	key         []byte
+	allowLegacy bool
+}
+
+// NewCursorCodec creates a codec that signs the cursors with the key, if the key is empty the cursors aren't signed
+func NewCursorCodec(key []byte) *CursorCodec {
+	return &CursorCodec{key: key, allowLegacy: len(key) == 0}
+}
+
+// AllowLegacy accepts the cursors of the previous format even if the codec has a key, they aren't signed
+// so it should only be used while the clients move to the signed cursors
+func (c *CursorCodec) AllowLegacy() *CursorCodec {
+	c.allowLegacy = true
+	return c
+}
+
+// DisableLegacy rejects the cursors of the previous format
+func (c *CursorCodec) DisableLegacy() *CursorCodec {
+	c.allowLegacy = false
+	return c
+}
+
+// Encode returns the cursor as a string with the version, the base64 of the values and the signature
+func (c *CursorCodec) Encode(cursor Cursor) (string, error) {
+	data, err := json.Marshal(cursor.Values)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode cursor: %w", err)
+	}
+
+	payload := cursorVersion + "." + base64.RawURLEncoding.EncodeToString(data)
+	if len(c.key) == 0 {
+		return payload, nil
+	}
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode returns the cursor of the string, the numbers are returned as json.Number and the times as strings
+func (c *CursorCodec) Decode(s string) (Cursor, error) {
+	if !strings.HasPrefix(s, cursorVersion+".") {
+		if !c.allowLegacy {
+			return Cursor{}, fmt.Errorf("%w: unknown version", ErrInvalidCursor)
+		}
+		return decodeLegacyCursor(s)
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Cursor{}, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	if len(c.key) > 0 {
+		if len(parts) != 3 {
+			return Cursor{}, fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil || !hmac.Equal(sig, c.sign(parts[0]+"."+parts[1])) {
+			return Cursor{}, fmt.Errorf("%w: wrong signature", ErrInvalidCursor)
+		}
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values []interface{}
+	if err := dec.Decode(&values); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	return Cursor{Values: values}, nil
+}
+
+func (c *CursorCodec) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// decodeLegacyCursor decodes the cursors of CreateCursorWithIdAndTime before they were versioned,
+// the id is everything before the last * so ids containing * are kept
+func decodeLegacyCursor(s string) (Cursor, error) {
+	res, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: unable to decode cursor: %s", ErrInvalidCursor, err)
+	}
+
+	str := string(res)
+	sep := strings.LastIndex(str, "*")
+	if sep < 0 {
+		return Cursor{}, fmt.Errorf("%w: cursor must have two values", ErrInvalidCursor)
+	}
+	created, err := time.Parse(time.RFC3339, str[sep+1:])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: unable to parse cursor time: %s", ErrInvalidCursor, err)
+	}
+
+	return NewCursor(created, str[:sep]), nil
+}
@@ -0,0 +1,100 @@
+package component
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCursorCodec(t *testing.T) {
+	created := time.Date(2020, 6, 1, 10, 0, 0, 123456789, time.UTC)
+	signed := NewCursorCodec([]byte("key"))
+	encoded, err := signed.Encode(NewCursor(created, "a*b", 3))
+	assert.NoError(t, err)
+	unsigned, err := NewCursorCodec(nil).Encode(NewCursor(created, "a*b", 3))
+	assert.NoError(t, err)
+	legacy := base64.StdEncoding.EncodeToString([]byte("a*b*2020-06-01T10:00:00Z"))
+
+	testCases := map[string]struct {
+		codec          *CursorCodec
+		cursor         string
+		expectedValues []interface{}
+		expectedErr    bool
+	}{
+		"a signed cursor should keep the nanoseconds and the values": {
+			codec:          signed,
+			cursor:         encoded,
+			expectedValues: []interface{}{"2020-06-01T10:00:00.123456789Z", "a*b", json.Number("3")},
+		},
+		"a cursor signed with another key should be rejected": {
+			codec:       NewCursorCodec([]byte("other")),
+			cursor:      encoded,
+			expectedErr: true,
+		},
+		"an unsigned cursor should be rejected if there is a key": {
+			codec:       signed,
+			cursor:      unsigned,
+			expectedErr: true,
+		},
+		"a legacy cursor should be decoded as time and id": {
+			codec:          NewCursorCodec(nil),
+			cursor:         legacy,
+			expectedValues: []interface{}{time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), "a*b"},
+		},
+		"a legacy cursor should be rejected if there is a key": {
+			codec:       signed,
+			cursor:      legacy,
+			expectedErr: true,
+		},
+		"a legacy cursor should be decoded if there is a key and they are allowed": {
+			codec:          NewCursorCodec([]byte("key")).AllowLegacy(),
+			cursor:         legacy,
+			expectedValues: []interface{}{time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC), "a*b"},
+		},
+		"a legacy cursor should be rejected if they are disabled": {
+			codec:       NewCursorCodec(nil).DisableLegacy(),
+			cursor:      legacy,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c, err := tc.codec.Decode(tc.cursor)
+			if tc.expectedErr {
+				assert.True(t, errors.Is(err, ErrInvalidCursor))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValues, c.Values)
+		})
+	}
+}
+
+func TestIDTimeCursor(t *testing.T) {
+	a := NewArango("test", nil, "", "", OptionCursorKey([]byte("key")))
+	created := time.Date(2020, 6, 1, 10, 0, 0, 5, time.UTC)
+
+	id, got, err := a.GetIDTimeCursor(a.CreateCursorWithIdAndTime("users/1", created))
+	assert.NoError(t, err)
+	assert.Equal(t, "users/1", id)
+	assert.True(t, created.Equal(got))
+}
+
+func TestEnhanceBindVarsWithIdTimeCursor(t *testing.T) {
+	a := NewArango("test", nil, "", "", OptionCursorKey([]byte("key")))
+	created := time.Date(2020, 6, 1, 10, 0, 0, 5, time.UTC)
+	cursor := a.CreateCursorWithIdAndTime("users/1", created)
+
+	bindVars, err := a.EnhanceBindVarsWithIdTimeCursor(map[string]interface{}{}, Pagination{Limit: 10, After: cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, true, bindVars["paginationOn"])
+	assert.Equal(t, "users/1", bindVars["paginationAfterId"])
+	assert.Equal(t, 11, bindVars["paginationLimit"])
+
+	_, err = a.EnhanceBindVarsWithIdTimeCursor(map[string]interface{}{}, Pagination{Limit: 10, Before: cursor})
+	assert.EqualError(t, err, "pagination with a before cursor is not supported")
+}
@@ -0,0 +1,246 @@
+package component
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	dumpExtension          = ".jsonl"
+	defaultImportBatchSize = 1000
+)
+
+// ImportOptions tells how the documents are imported, with the zero value they are imported in batches
+// of 1000 documents, keeping their keys and failing if a key already exists
+type ImportOptions struct {
+	BatchSize int
+	// OnDuplicate is what happens when a document with the same key exists, error, update, replace or ignore
+	OnDuplicate driver.ImportOnDuplicate
+	// Overwrite removes the documents of the collection before importing the first batch, or truncates it
+	// if there are no documents
+	Overwrite bool
+	// DiscardKeys removes the _key of the documents so the database generates new ones,
+	// the edges keep their _from and _to
+	DiscardKeys bool
+}
+
+// Export writes the documents of the collections to dir, one JSON document per line in a file named after
+// the collection with the .jsonl extension. If there are no collections all the ones that aren't system
+// collections are exported. The documents are sorted by key and the _id and _rev are removed,
+// so the files only change when the data does
+func (a *Arango) Export(ctx context.Context, dir string, collections []string) error {
+	if len(collections) == 0 {
+		var err error
+		if collections, err = a.userCollections(ctx); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create dir '%s': %w", dir, err)
+	}
+
+	for _, name := range collections {
+		f, err := os.Create(filepath.Join(dir, name+dumpExtension))
+		if err != nil {
+			return fmt.Errorf("unable to create file of '%s': %w", name, err)
+		}
+		_, err = a.ExportCollection(ctx, name, f)
+		if cErr := f.Close(); err == nil && cErr != nil {
+			err = fmt.Errorf("unable to close file of '%s': %w", name, cErr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportCollection writes the documents of the collection to w as JSON lines and returns how many were written
+func (a *Arango) ExportCollection(ctx context.Context, collection string, w io.Writer) (int, error) {
+	cursor, err := a.clientDB.Query(ctx, "FOR d IN @@collection SORT d._key RETURN UNSET(d, '_id', '_rev')",
+		map[string]interface{}{"@collection": collection})
+	if err != nil {
+		return 0, fmt.Errorf("unable to read collection '%s': %w", collection, err)
+	}
+	defer cursor.Close()
+
+	buf := bufio.NewWriter(w)
+	count := 0
+	for cursor.HasMore() {
+		var doc json.RawMessage
+		if _, err := cursor.ReadDocument(ctx, &doc); err != nil {
+			return count, fmt.Errorf("unable to read document of '%s': %w", collection, err)
+		}
+		var line bytes.Buffer
+		if err := json.Compact(&line, doc); err != nil {
+			return count, fmt.Errorf("unable to encode document of '%s': %w", collection, err)
+		}
+		line.WriteByte('\n')
+		if _, err := buf.Write(line.Bytes()); err != nil {
+			return count, fmt.Errorf("unable to write document of '%s': %w", collection, err)
+		}
+		count++
+	}
+	if err := buf.Flush(); err != nil {
+		return count, fmt.Errorf("unable to write documents of '%s': %w", collection, err)
+	}
+
+	return count, nil
+}
+
+// Import reads the documents of the collections from the files in dir written by Export, if there are
+// no collections all the .jsonl files of dir are imported. The collections must exist
+func (a *Arango) Import(ctx context.Context, dir string, collections []string, opts ImportOptions) error {
+	if len(collections) == 0 {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("unable to read dir '%s': %w", dir, err)
+		}
+		for _, f := range files {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), dumpExtension) {
+				collections = append(collections, strings.TrimSuffix(f.Name(), dumpExtension))
+			}
+		}
+	}
+
+	for _, name := range collections {
+		f, err := os.Open(filepath.Join(dir, name+dumpExtension))
+		if err != nil {
+			return fmt.Errorf("unable to open file of '%s': %w", name, err)
+		}
+		_, err = a.ImportCollection(ctx, name, f, opts)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportCollection imports the JSON lines of r into the collection and returns the statistics of all the batches,
+// every batch is imported completely or not at all
+func (a *Arango) ImportCollection(ctx context.Context, collection string, r io.Reader, opts ImportOptions) (driver.ImportDocumentStatistics, error) {
+	var stats driver.ImportDocumentStatistics
+	c, err := a.clientDB.Collection(ctx, collection)
+	if err != nil {
+		return stats, fmt.Errorf("unable to get collection '%s': %w", collection, err)
+	}
+
+	overwrite := opts.Overwrite
+	err = readDocumentBatches(r, opts, func(batch []map[string]interface{}) error {
+		s, err := c.ImportDocuments(ctx, batch, &driver.ImportDocumentOptions{
+			OnDuplicate: opts.OnDuplicate,
+			Overwrite:   overwrite,
+			Complete:    true,
+		})
+		overwrite = false
+		if err != nil {
+			return fmt.Errorf("unable to import documents into '%s': %w", collection, err)
+		}
+		stats.Created += s.Created
+		stats.Updated += s.Updated
+		stats.Ignored += s.Ignored
+		stats.Errors += s.Errors
+		stats.Empty += s.Empty
+
+		return nil
+	})
+	if err == nil && overwrite {
+		// no batch was imported, the collection is emptied as the overwrite of the first one would have done
+		if err := c.Truncate(ctx); err != nil {
+			return stats, fmt.Errorf("unable to truncate collection '%s': %w", collection, err)
+		}
+	}
+
+	return stats, err
+}
+
+// TruncateAll removes the documents of all the collections that aren't system collections, it's meant
+// to reset the databases of the tests
+func (a *Arango) TruncateAll(ctx context.Context) error {
+	collections, err := a.userCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range collections {
+		c, err := a.clientDB.Collection(ctx, name)
+		if err != nil {
+			return fmt.Errorf("unable to get collection '%s': %w", name, err)
+		}
+		if err := c.Truncate(ctx); err != nil {
+			return fmt.Errorf("unable to truncate collection '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *Arango) userCollections(ctx context.Context) ([]string, error) {
+	collections, err := a.clientDB.Collections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list collections: %w", err)
+	}
+
+	names := make([]string, 0, len(collections))
+	for _, c := range collections {
+		if !strings.HasPrefix(c.Name(), "_") {
+			names = append(names, c.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// readDocumentBatches calls fn with the documents of the JSON lines of r in batches, the empty lines are skipped
+func readDocumentBatches(r io.Reader, opts ImportOptions, fn func(batch []map[string]interface{}) error) error {
+	size := opts.BatchSize
+	if size <= 0 {
+		size = defaultImportBatchSize
+	}
+
+	reader := bufio.NewReader(r)
+	batch := make([]map[string]interface{}, 0, size)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("unable to read line %d: %w", line, err)
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(trimmed))
+			dec.UseNumber()
+			var doc map[string]interface{}
+			if err := dec.Decode(&doc); err != nil {
+				return fmt.Errorf("unable to parse line %d: %w", line, err)
+			}
+			if opts.DiscardKeys {
+				delete(doc, "_key")
+			}
+			delete(doc, "_id")
+			delete(doc, "_rev")
+			batch = append(batch, doc)
+		}
+
+		if len(batch) == size || (err == io.EOF && len(batch) > 0) {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]map[string]interface{}, 0, size)
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestReadDocumentBatches(t *testing.T) {
+	input := `{"_key":"1","_rev":"a","n":12345678901234567}
+{"_key":"2","_id":"users/2"}
+
+{"_key":"3"}`
+	testCases := map[string]struct {
+		input           string
+		opts            ImportOptions
+		expectedBatches [][]map[string]interface{}
+		expectedErr     string
+	}{
+		"the documents should be grouped in batches without _id and _rev": {
+			input: input,
+			opts:  ImportOptions{BatchSize: 2},
+			expectedBatches: [][]map[string]interface{}{
+				{{"_key": "1", "n": json.Number("12345678901234567")}, {"_key": "2"}},
+				{{"_key": "3"}},
+			},
+		},
+		"if the keys are discarded the documents shouldn't have keys": {
+			input: input,
+			opts:  ImportOptions{DiscardKeys: true},
+			expectedBatches: [][]map[string]interface{}{
+				{{"n": json.Number("12345678901234567")}, {}, {}},
+			},
+		},
+		"if a line isn't a document it should fail": {
+			input:       "{\"_key\":\"1\"}\nwrong\n",
+			expectedErr: "unable to parse line 2: invalid character 'w' looking for beginning of value",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var batches [][]map[string]interface{}
+			err := readDocumentBatches(strings.NewReader(tc.input), tc.opts, func(batch []map[string]interface{}) error {
+				batches = append(batches, batch)
+				return nil
+			})
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBatches, batches)
+		})
+	}
+
+	err := readDocumentBatches(strings.NewReader(input), ImportOptions{}, func([]map[string]interface{}) error {
+		return fmt.Errorf("test")
+	})
+	assert.EqualError(t, err, "test")
+}
+
+func TestImportCollection(t *testing.T) {
+	testCases := map[string]struct {
+		input         string
+		opts          ImportOptions
+		expectedCalls []string
+		overwrite     string
+	}{
+		"with overwrite the first batch should replace the documents": {
+			input:         "{\"_key\":\"1\"}\n",
+			opts:          ImportOptions{Overwrite: true},
+			expectedCalls: []string{"GET _api/collection/users", "POST _api/import"},
+			overwrite:     "true",
+		},
+		"with overwrite and no documents the collection should be truncated": {
+			opts:          ImportOptions{Overwrite: true},
+			expectedCalls: []string{"GET _api/collection/users", "PUT _api/collection/users/truncate"},
+		},
+		"without overwrite and no documents nothing should be changed": {
+			expectedCalls: []string{"GET _api/collection/users"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a, srv := newTestArango(t)
+
+			_, err := a.ImportCollection(context.Background(), "users", strings.NewReader(tc.input), tc.opts)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCalls, srv.calls())
+			if tc.overwrite != "" {
+				assert.Equal(t, tc.overwrite, srv.requests[1].Query["overwrite"])
+			}
+		})
+	}
+}
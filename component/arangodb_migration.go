@@ -0,0 +1,503 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const migrationLockKey = "lock"
+
+// ErrMigrationLockLost is returned when the lock expired and was taken by another replica while migrating
+var ErrMigrationLockLost = errors.New("migration lock lost")
+
+// MigrationFunc applies, or reverts, a change in the database
+type MigrationFunc func(ctx context.Context, a *Arango) error
+
+// Migration is a versioned change of the schema or the data, migrations are applied in ascending order of Version
+// and reverted in descending order. Down is optional, a migration without it can't be reverted
+type Migration struct {
+	Version     int
+	Description string
+	Up          MigrationFunc
+	Down        MigrationFunc
+}
+
+// AppliedMigration is the record stored in the migrations collection for every applied migration
+type AppliedMigration struct {
+	Key         string    `json:"_key,omitempty"`
+	Version     int       `json:"version"`
+	Description string    `json:"description"`
+	AppliedAt   time.Time `json:"appliedAt"`
+}
+
+type migrationLock struct {
+	Key       string    `json:"_key"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type OptionMigrator func(*Migrator)
+
+// Migrator applies the migrations on top of an initialised Arango, keeping track of the applied versions in a
+// system collection. A lock document is taken while migrating so only one replica runs the migrations, the rest
+// wait until it's released
+type Migrator struct {
+	arango        *Arango
+	migrations    []Migration
+	collection    string
+	owner         string
+	lockTTL       time.Duration
+	retryInterval time.Duration
+}
+
+func NewMigrator(arango *Arango, migrations []Migration, options ...OptionMigrator) *Migrator {
+	owner, _ := os.Hostname()
+	m := &Migrator{
+		arango:        arango,
+		migrations:    append([]Migration{}, migrations...),
+		collection:    "_migrations",
+		owner:         fmt.Sprintf("%s-%d", owner, os.Getpid()),
+		lockTTL:       time.Minute * 5,
+		retryInterval: time.Second,
+	}
+	for _, o := range options {
+		o(m)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	return m
+}
+
+// OptionMigrationsCollection sets the collection where the applied versions are stored, it must start with '_'
+func OptionMigrationsCollection(name string) OptionMigrator {
+	return func(m *Migrator) {
+		m.collection = name
+	}
+}
+
+// OptionMigrationLockTTL sets for how long the lock is valid, if the replica holding it dies
+// the lock can be taken by another one after this time. While migrating the lock is refreshed
+// every third of the TTL
+func OptionMigrationLockTTL(t time.Duration) OptionMigrator {
+	return func(m *Migrator) {
+		m.lockTTL = t
+	}
+}
+
+// OptionMigrationLockRetry sets how often a replica tries to take the lock while another one is migrating
+func OptionMigrationLockRetry(t time.Duration) OptionMigrator {
+	return func(m *Migrator) {
+		m.retryInterval = t
+	}
+}
+
+// Applied returns the migrations already applied, sorted by version
+func (m *Migrator) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	if err := m.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	cursor, err := m.arango.DB().Query(ctx,
+		"FOR m IN @@col FILTER m._key != @lock SORT m.version RETURN m",
+		map[string]interface{}{"@col": m.collection, "lock": migrationLockKey})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query applied migrations: %w", err)
+	}
+	defer cursor.Close()
+
+	applied := make([]AppliedMigration, 0)
+	for cursor.HasMore() {
+		var am AppliedMigration
+		if _, err := cursor.ReadDocument(ctx, &am); err != nil {
+			return nil, fmt.Errorf("unable to read applied migration: %w", err)
+		}
+		applied = append(applied, am)
+	}
+
+	return applied, nil
+}
+
+// Plan returns the migrations that Up would apply, without applying them
+func (m *Migrator) Plan(ctx context.Context) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingMigrations(m.migrations, applied), nil
+}
+
+// PlanDown returns the migrations that Down would revert to leave the database at the given version,
+// without reverting them
+func (m *Migrator) PlanDown(ctx context.Context, version int) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return revertMigrations(m.migrations, applied, version), nil
+}
+
+// pendingMigrations returns the migrations not applied, the migrations must be sorted by version
+func pendingMigrations(migrations []Migration, applied map[int]bool) []Migration {
+	pending := make([]Migration, 0)
+	for _, mig := range migrations {
+		if !applied[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+
+	return pending
+}
+
+// revertMigrations returns the migrations applied above the version in descending order,
+// the migrations must be sorted by version
+func revertMigrations(migrations []Migration, applied map[int]bool, version int) []Migration {
+	revert := make([]Migration, 0)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mig := migrations[i]
+		if mig.Version > version && applied[mig.Version] {
+			revert = append(revert, mig)
+		}
+	}
+
+	return revert
+}
+
+// Up applies every pending migration in order
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(ctx context.Context) error {
+		pending, err := m.Plan(ctx)
+		if err != nil {
+			return err
+		}
+
+		col, err := m.arango.DB().Collection(ctx, m.collection)
+		if err != nil {
+			return fmt.Errorf("unable to get migrations collection: %w", err)
+		}
+
+		for _, mig := range pending {
+			if mig.Up == nil {
+				return fmt.Errorf("migration %d has no up function", mig.Version)
+			}
+			if err := mig.Up(ctx, m.arango); err != nil {
+				return fmt.Errorf("unable to apply migration %d '%s': %w", mig.Version, mig.Description, err)
+			}
+
+			if _, err := col.CreateDocument(ctx, AppliedMigration{
+				Key:         strconv.Itoa(mig.Version),
+				Version:     mig.Version,
+				Description: mig.Description,
+				AppliedAt:   time.Now(),
+			}); err != nil {
+				return fmt.Errorf("unable to record migration %d: %w", mig.Version, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts, in descending order, every applied migration above the given version
+func (m *Migrator) Down(ctx context.Context, version int) error {
+	return m.withLock(ctx, func(ctx context.Context) error {
+		revert, err := m.PlanDown(ctx, version)
+		if err != nil {
+			return err
+		}
+
+		col, err := m.arango.DB().Collection(ctx, m.collection)
+		if err != nil {
+			return fmt.Errorf("unable to get migrations collection: %w", err)
+		}
+
+		for _, mig := range revert {
+			if mig.Down == nil {
+				return fmt.Errorf("migration %d has no down function", mig.Version)
+			}
+			if err := mig.Down(ctx, m.arango); err != nil {
+				return fmt.Errorf("unable to revert migration %d '%s': %w", mig.Version, mig.Description, err)
+			}
+
+			if _, err := col.RemoveDocument(ctx, strconv.Itoa(mig.Version)); err != nil {
+				return fmt.Errorf("unable to remove migration record %d: %w", mig.Version, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[int]bool, len(applied))
+	for _, am := range applied {
+		versions[am.Version] = true
+	}
+
+	return versions, nil
+}
+
+func (m *Migrator) ensureCollection(ctx context.Context) error {
+	if m.arango.DB() == nil {
+		return errors.New("arango not initialized, InitDB must be called first")
+	}
+
+	_, err := m.arango.DB().CreateCollection(ctx, m.collection, &driver.CreateCollectionOptions{IsSystem: true})
+	if err != nil && !driver.IsArangoErrorWithErrorNum(err, ErrDuplicate) {
+		return fmt.Errorf("unable to create migrations collection: %w", err)
+	}
+
+	return nil
+}
+
+// lockCollection is the part of driver.Collection used to hold the migration lock
+type lockCollection interface {
+	CreateDocument(ctx context.Context, document interface{}) (driver.DocumentMeta, error)
+	ReadDocument(ctx context.Context, key string, result interface{}) (driver.DocumentMeta, error)
+	UpdateDocument(ctx context.Context, key string, update interface{}) (driver.DocumentMeta, error)
+	RemoveDocument(ctx context.Context, key string) (driver.DocumentMeta, error)
+}
+
+// withLock waits until the migration lock is taken, runs fn and releases it
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	col, err := m.arango.DB().Collection(ctx, m.collection)
+	if err != nil {
+		return fmt.Errorf("unable to get migrations collection: %w", err)
+	}
+
+	return m.runLocked(ctx, col, fn)
+}
+
+// runLocked holds the lock while fn runs, refreshing it before it expires. If the lock can't be refreshed
+// the context of fn is canceled, since another replica may take the lock and migrate at the same time
+func (m *Migrator) runLocked(ctx context.Context, col lockCollection, fn func(ctx context.Context) error) error {
+	var rev string
+	for {
+		acquired, r, err := m.acquireLock(ctx, col)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			rev = r
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to acquire migration lock: %w", ctx.Err())
+		case <-time.After(m.retryInterval):
+		}
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan struct{})
+	var hbErr error
+	go func() {
+		defer close(done)
+		if rev, hbErr = m.heartbeat(fnCtx, col, rev); hbErr != nil {
+			cancel()
+		}
+	}()
+
+	err := fn(fnCtx)
+	cancel()
+	<-done
+
+	if hbErr != nil {
+		_ = m.releaseLock(col, rev)
+		return fmt.Errorf("migration aborted: %w", hbErr)
+	}
+	if rErr := m.releaseLock(col, rev); rErr != nil && err == nil {
+		err = rErr
+	}
+
+	return err
+}
+
+// heartbeat pushes the expiration of the lock forward until ctx is done and returns the last revision of the lock
+func (m *Migrator) heartbeat(ctx context.Context, col lockCollection, rev string) (string, error) {
+	ticker := time.NewTicker(m.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return rev, nil
+		case <-ticker.C:
+		}
+
+		// the update doesn't use ctx, if it was canceled halfway the revision of the lock would be unknown
+		updateCtx, cancel := context.WithTimeout(context.Background(), m.lockTTL/3)
+		meta, err := col.UpdateDocument(driver.WithRevision(updateCtx, rev), migrationLockKey,
+			map[string]interface{}{"expiresAt": time.Now().Add(m.lockTTL)})
+		cancel()
+		switch {
+		case driver.IsPreconditionFailed(err) || driver.IsNotFound(err):
+			return rev, ErrMigrationLockLost
+		case err != nil:
+			return rev, fmt.Errorf("unable to refresh migration lock: %w", err)
+		}
+		rev = meta.Rev
+	}
+}
+
+// releaseLock removes the lock only if it's still the one taken by this replica
+func (m *Migrator) releaseLock(col lockCollection, rev string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := col.RemoveDocument(driver.WithRevision(ctx, rev), migrationLockKey)
+	switch {
+	case driver.IsPreconditionFailed(err) || driver.IsNotFound(err):
+		return fmt.Errorf("unable to release migration lock: %w", ErrMigrationLockLost)
+	case err != nil:
+		return fmt.Errorf("unable to release migration lock: %w", err)
+	}
+
+	return nil
+}
+
+// acquireLock creates the lock document and returns its revision, if another replica holds it
+// it's removed once it expires so it can be taken in the next attempt
+func (m *Migrator) acquireLock(ctx context.Context, col lockCollection) (bool, string, error) {
+	meta, err := col.CreateDocument(ctx, migrationLock{
+		Key:       migrationLockKey,
+		Owner:     m.owner,
+		ExpiresAt: time.Now().Add(m.lockTTL),
+	})
+	if err == nil {
+		return true, meta.Rev, nil
+	}
+	if !driver.IsArangoErrorWithErrorNum(err, ErrUniqueConstraintViolated) {
+		return false, "", fmt.Errorf("unable to create migration lock: %w", err)
+	}
+
+	var lock migrationLock
+	meta, err = col.ReadDocument(ctx, migrationLockKey, &lock)
+	if driver.IsNotFound(err) {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("unable to read migration lock: %w", err)
+	}
+
+	if time.Now().After(lock.ExpiresAt) {
+		// the owner of the lock died, it's removed only if nobody else took or refreshed it meanwhile
+		_, err := col.RemoveDocument(driver.WithRevision(ctx, meta.Rev), migrationLockKey)
+		if err != nil && !driver.IsPreconditionFailed(err) && !driver.IsNotFound(err) {
+			return false, "", fmt.Errorf("unable to remove expired migration lock: %w", err)
+		}
+	}
+
+	return false, "", nil
+}
+
+// MigrateCollections returns a MigrationFunc that creates the collections and their indexes
+func MigrateCollections(collections []Collection) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		return a.CreateCollections(ctx, collections)
+	}
+}
+
+// MigrateGraph returns a MigrationFunc that creates the graph along with its vertexes and edges
+func MigrateGraph(graph *Graph) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		return a.CreateGraph(ctx, graph)
+	}
+}
+
+// MigrateAQL returns a MigrationFunc that runs the AQL query, meant for data fixes
+func MigrateAQL(query string, bindVars map[string]interface{}) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		cursor, err := a.DB().Query(ctx, query, bindVars)
+		if err != nil {
+			return fmt.Errorf("unable to run query: %w", err)
+		}
+
+		return cursor.Close()
+	}
+}
+
+// DropCollections returns a MigrationFunc that removes the collections, missing ones are ignored
+func DropCollections(names ...string) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		for _, name := range names {
+			col, err := a.DB().Collection(ctx, name)
+			if driver.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("unable to get collection '%s': %w", name, err)
+			}
+			if err := col.Remove(ctx); err != nil {
+				return fmt.Errorf("unable to remove collection '%s': %w", name, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// DropIndexes returns a MigrationFunc that removes the indexes of the collection, missing ones are ignored
+func DropIndexes(collection string, names ...string) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		col, err := a.DB().Collection(ctx, collection)
+		if err != nil {
+			return fmt.Errorf("unable to get collection '%s': %w", collection, err)
+		}
+
+		for _, name := range names {
+			ix, err := col.Index(ctx, name)
+			if driver.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("unable to get index '%s': %w", name, err)
+			}
+			if err := ix.Remove(ctx); err != nil {
+				return fmt.Errorf("unable to remove index '%s': %w", name, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// DropGraph returns a MigrationFunc that removes the graph, its collections are kept
+func DropGraph(name string) MigrationFunc {
+	return func(ctx context.Context, a *Arango) error {
+		g, err := a.DB().Graph(ctx, name)
+		if driver.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to get graph '%s': %w", name, err)
+		}
+
+		if err := g.Remove(ctx); err != nil {
+			return fmt.Errorf("unable to remove graph '%s': %w", name, err)
+		}
+
+		return nil
+	}
+}
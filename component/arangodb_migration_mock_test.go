@@ -0,0 +1,243 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package component
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"sync"
+)
+
+var (
+	locklockCollectionMockCreateDocument sync.RWMutex
+	locklockCollectionMockReadDocument   sync.RWMutex
+	locklockCollectionMockRemoveDocument sync.RWMutex
+	locklockCollectionMockUpdateDocument sync.RWMutex
+)
+
+// Ensure, that lockCollectionMock does implement lockCollection.
+// If this is not the case, regenerate this file with moq.
+var _ lockCollection = &lockCollectionMock{}
+
+// lockCollectionMock is a mock implementation of lockCollection.
+//
+//     func TestSomethingThatUseslockCollection(t *testing.T) {
+//
+//         // make and configure a mocked lockCollection
+//         mockedlockCollection := &lockCollectionMock{
+//             CreateDocumentFunc: func(ctx context.Context, document interface{}) (driver.DocumentMeta, error) {
+// 	               panic("mock out the CreateDocument method")
+//             },
+//             ReadDocumentFunc: func(ctx context.Context, key string, result interface{}) (driver.DocumentMeta, error) {
+// 	               panic("mock out the ReadDocument method")
+//             },
+//             RemoveDocumentFunc: func(ctx context.Context, key string) (driver.DocumentMeta, error) {
+// 	               panic("mock out the RemoveDocument method")
+//             },
+//             UpdateDocumentFunc: func(ctx context.Context, key string, update interface{}) (driver.DocumentMeta, error) {
+// 	               panic("mock out the UpdateDocument method")
+//             },
+//         }
+//
+//         // use mockedlockCollection in code that requires lockCollection
+//         // and then make assertions.
+//
+//     }
+type lockCollectionMock struct {
+	// CreateDocumentFunc mocks the CreateDocument method.
+	CreateDocumentFunc func(ctx context.Context, document interface{}) (driver.DocumentMeta, error)
+
+	// ReadDocumentFunc mocks the ReadDocument method.
+	ReadDocumentFunc func(ctx context.Context, key string, result interface{}) (driver.DocumentMeta, error)
+
+	// RemoveDocumentFunc mocks the RemoveDocument method.
+	RemoveDocumentFunc func(ctx context.Context, key string) (driver.DocumentMeta, error)
+
+	// UpdateDocumentFunc mocks the UpdateDocument method.
+	UpdateDocumentFunc func(ctx context.Context, key string, update interface{}) (driver.DocumentMeta, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateDocument holds details about calls to the CreateDocument method.
+		CreateDocument []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Document is the document argument value.
+			Document interface{}
+		}
+		// ReadDocument holds details about calls to the ReadDocument method.
+		ReadDocument []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Result is the result argument value.
+			Result interface{}
+		}
+		// RemoveDocument holds details about calls to the RemoveDocument method.
+		RemoveDocument []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// UpdateDocument holds details about calls to the UpdateDocument method.
+		UpdateDocument []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Update is the update argument value.
+			Update interface{}
+		}
+	}
+}
+
+// CreateDocument calls CreateDocumentFunc.
+func (mock *lockCollectionMock) CreateDocument(ctx context.Context, document interface{}) (driver.DocumentMeta, error) {
+	if mock.CreateDocumentFunc == nil {
+		panic("lockCollectionMock.CreateDocumentFunc: method is nil but lockCollection.CreateDocument was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Document interface{}
+	}{
+		Ctx:      ctx,
+		Document: document,
+	}
+	locklockCollectionMockCreateDocument.Lock()
+	mock.calls.CreateDocument = append(mock.calls.CreateDocument, callInfo)
+	locklockCollectionMockCreateDocument.Unlock()
+	return mock.CreateDocumentFunc(ctx, document)
+}
+
+// CreateDocumentCalls gets all the calls that were made to CreateDocument.
+// Check the length with:
+//     len(mockedlockCollection.CreateDocumentCalls())
+func (mock *lockCollectionMock) CreateDocumentCalls() []struct {
+	Ctx      context.Context
+	Document interface{}
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Document interface{}
+	}
+	locklockCollectionMockCreateDocument.RLock()
+	calls = mock.calls.CreateDocument
+	locklockCollectionMockCreateDocument.RUnlock()
+	return calls
+}
+
+// ReadDocument calls ReadDocumentFunc.
+func (mock *lockCollectionMock) ReadDocument(ctx context.Context, key string, result interface{}) (driver.DocumentMeta, error) {
+	if mock.ReadDocumentFunc == nil {
+		panic("lockCollectionMock.ReadDocumentFunc: method is nil but lockCollection.ReadDocument was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Key    string
+		Result interface{}
+	}{
+		Ctx:    ctx,
+		Key:    key,
+		Result: result,
+	}
+	locklockCollectionMockReadDocument.Lock()
+	mock.calls.ReadDocument = append(mock.calls.ReadDocument, callInfo)
+	locklockCollectionMockReadDocument.Unlock()
+	return mock.ReadDocumentFunc(ctx, key, result)
+}
+
+// ReadDocumentCalls gets all the calls that were made to ReadDocument.
+// Check the length with:
+//     len(mockedlockCollection.ReadDocumentCalls())
+func (mock *lockCollectionMock) ReadDocumentCalls() []struct {
+	Ctx    context.Context
+	Key    string
+	Result interface{}
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Key    string
+		Result interface{}
+	}
+	locklockCollectionMockReadDocument.RLock()
+	calls = mock.calls.ReadDocument
+	locklockCollectionMockReadDocument.RUnlock()
+	return calls
+}
+
+// RemoveDocument calls RemoveDocumentFunc.
+func (mock *lockCollectionMock) RemoveDocument(ctx context.Context, key string) (driver.DocumentMeta, error) {
+	if mock.RemoveDocumentFunc == nil {
+		panic("lockCollectionMock.RemoveDocumentFunc: method is nil but lockCollection.RemoveDocument was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	locklockCollectionMockRemoveDocument.Lock()
+	mock.calls.RemoveDocument = append(mock.calls.RemoveDocument, callInfo)
+	locklockCollectionMockRemoveDocument.Unlock()
+	return mock.RemoveDocumentFunc(ctx, key)
+}
+
+// RemoveDocumentCalls gets all the calls that were made to RemoveDocument.
+// Check the length with:
+//     len(mockedlockCollection.RemoveDocumentCalls())
+func (mock *lockCollectionMock) RemoveDocumentCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	locklockCollectionMockRemoveDocument.RLock()
+	calls = mock.calls.RemoveDocument
+	locklockCollectionMockRemoveDocument.RUnlock()
+	return calls
+}
+
+// UpdateDocument calls UpdateDocumentFunc.
+func (mock *lockCollectionMock) UpdateDocument(ctx context.Context, key string, update interface{}) (driver.DocumentMeta, error) {
+	if mock.UpdateDocumentFunc == nil {
+		panic("lockCollectionMock.UpdateDocumentFunc: method is nil but lockCollection.UpdateDocument was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Key    string
+		Update interface{}
+	}{
+		Ctx:    ctx,
+		Key:    key,
+		Update: update,
+	}
+	locklockCollectionMockUpdateDocument.Lock()
+	mock.calls.UpdateDocument = append(mock.calls.UpdateDocument, callInfo)
+	locklockCollectionMockUpdateDocument.Unlock()
+	return mock.UpdateDocumentFunc(ctx, key, update)
+}
+
+// UpdateDocumentCalls gets all the calls that were made to UpdateDocument.
+// Check the length with:
+//     len(mockedlockCollection.UpdateDocumentCalls())
+func (mock *lockCollectionMock) UpdateDocumentCalls() []struct {
+	Ctx    context.Context
+	Key    string
+	Update interface{}
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Key    string
+		Update interface{}
+	}
+	locklockCollectionMockUpdateDocument.RLock()
+	calls = mock.calls.UpdateDocument
+	locklockCollectionMockUpdateDocument.RUnlock()
+	return calls
+}
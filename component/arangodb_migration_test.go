@@ -0,0 +1,172 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMigrationPlans(t *testing.T) {
+	m := NewMigrator(nil, []Migration{{Version: 3}, {Version: 1}, {Version: 4}, {Version: 2}})
+	applied := map[int]bool{1: true, 2: true, 4: true}
+
+	assert.Equal(t, []Migration{{Version: 3}}, pendingMigrations(m.migrations, applied))
+	assert.Equal(t, []Migration{{Version: 4}, {Version: 2}}, revertMigrations(m.migrations, applied, 1))
+	assert.Equal(t, []Migration{}, revertMigrations(m.migrations, applied, 4))
+}
+
+// lockState is the lock document of the migrations collection, with its revision
+type lockState struct {
+	mu      sync.Mutex
+	lock    *migrationLock
+	rev     int
+	updates int
+}
+
+func (s *lockState) steal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lock = &migrationLock{Key: migrationLockKey, Owner: "other", ExpiresAt: time.Now().Add(time.Hour)}
+	s.rev++
+}
+
+// check returns the error of the driver if the lock doesn't exist or the revision of ctx doesn't match
+func (s *lockState) check(ctx context.Context) error {
+	if s.lock == nil {
+		return driver.ArangoError{HasError: true, Code: 404, ErrorNum: ErrDocumentNotFound}
+	}
+	if rev, _ := ctx.Value(driver.ContextKey("arangodb-revision")).(string); rev != strconv.Itoa(s.rev) {
+		return driver.ArangoError{HasError: true, Code: 412, ErrorNum: ErrConflict}
+	}
+
+	return nil
+}
+
+func (s *lockState) collection() *lockCollectionMock {
+	return &lockCollectionMock{
+		CreateDocumentFunc: func(_ context.Context, document interface{}) (driver.DocumentMeta, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.lock != nil {
+				return driver.DocumentMeta{}, driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrUniqueConstraintViolated}
+			}
+			lock := document.(migrationLock)
+			s.lock = &lock
+			s.rev++
+			return driver.DocumentMeta{Rev: strconv.Itoa(s.rev)}, nil
+		},
+		ReadDocumentFunc: func(_ context.Context, _ string, result interface{}) (driver.DocumentMeta, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.lock == nil {
+				return driver.DocumentMeta{}, driver.ArangoError{HasError: true, Code: 404, ErrorNum: ErrDocumentNotFound}
+			}
+			*result.(*migrationLock) = *s.lock
+			return driver.DocumentMeta{Rev: strconv.Itoa(s.rev)}, nil
+		},
+		UpdateDocumentFunc: func(ctx context.Context, _ string, update interface{}) (driver.DocumentMeta, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err := s.check(ctx); err != nil {
+				return driver.DocumentMeta{}, err
+			}
+			s.lock.ExpiresAt = update.(map[string]interface{})["expiresAt"].(time.Time)
+			s.rev++
+			s.updates++
+			return driver.DocumentMeta{Rev: strconv.Itoa(s.rev)}, nil
+		},
+		RemoveDocumentFunc: func(ctx context.Context, _ string) (driver.DocumentMeta, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if err := s.check(ctx); err != nil {
+				return driver.DocumentMeta{}, err
+			}
+			s.lock = nil
+			return driver.DocumentMeta{}, nil
+		},
+	}
+}
+
+func TestMigrationLock(t *testing.T) {
+	testCases := map[string]struct {
+		lock            *migrationLock
+		fn              func(ctx context.Context, s *lockState) error
+		timeout         time.Duration
+		expectedErr     error
+		expectedCalled  bool
+		expectedOwner   string
+		expectedUpdates bool
+	}{
+		"if the lock is free it should run and release it": {
+			expectedCalled: true,
+		},
+		"if the lock expired it should take it": {
+			lock:           &migrationLock{Key: migrationLockKey, Owner: "other", ExpiresAt: time.Now().Add(-time.Second)},
+			expectedCalled: true,
+		},
+		"if the lock is held by another replica it should wait for it": {
+			lock:          &migrationLock{Key: migrationLockKey, Owner: "other", ExpiresAt: time.Now().Add(time.Hour)},
+			timeout:       time.Millisecond * 20,
+			expectedErr:   context.DeadlineExceeded,
+			expectedOwner: "other",
+		},
+		"if the migration takes longer than the TTL it should refresh the lock": {
+			fn: func(context.Context, *lockState) error {
+				time.Sleep(time.Millisecond * 80)
+				return nil
+			},
+			expectedCalled:  true,
+			expectedUpdates: true,
+		},
+		"if the lock is taken by another replica it should abort the migration and keep its lock": {
+			fn: func(ctx context.Context, s *lockState) error {
+				s.steal()
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			expectedErr:    ErrMigrationLockLost,
+			expectedCalled: true,
+			expectedOwner:  "other",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := &lockState{lock: tc.lock}
+			m := NewMigrator(nil, nil, OptionMigrationLockTTL(time.Millisecond*30), OptionMigrationLockRetry(time.Millisecond))
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			called := false
+			err := m.runLocked(ctx, s.collection(), func(ctx context.Context) error {
+				called = true
+				if tc.fn != nil {
+					return tc.fn(ctx, s)
+				}
+				return nil
+			})
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr), "unexpected error %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalled, called)
+			if tc.expectedOwner != "" {
+				assert.Equal(t, tc.expectedOwner, s.lock.Owner)
+			} else {
+				assert.Nil(t, s.lock)
+			}
+			assert.Equal(t, tc.expectedUpdates, s.updates > 0)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package component
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultPageLimits are the limits used by Arango unless they are configured with OptionPageLimits
+var DefaultPageLimits = PageLimits{Default: 20, Max: 100}
+
+// PageLimits are the number of documents of a page when the pagination has no limit, and the maximum allowed
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// Size returns the number of documents of the page of the pagination
+func (l PageLimits) Size(p Pagination) int {
+	switch {
+	case p.Limit <= 0:
+		return l.Default
+	case l.Max > 0 && p.Limit > l.Max:
+		return l.Max
+	default:
+		return p.Limit
+	}
+}
+
+// OptionPageLimits sets the default and maximum number of documents of the pages
+func OptionPageLimits(limits PageLimits) OptionArango {
+	return func(a *Arango) {
+		a.limits = limits
+	}
+}
+
+// Page is a page of a paginated list, HasMore and NextCursor refer to the direction of the pagination,
+// when paginating with Before the cursor is the one to use as Before of the previous page
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+	HasMore    bool        `json:"hasMore"`
+	Total      *int64      `json:"total,omitempty"`
+}
+
+// Paginator has the configuration of the pagination of a query, the one of Arango can be copied to change
+// the limits of a single query. The zero value uses DefaultPageLimits and cursors that aren't signed
+type Paginator struct {
+	Codec  *CursorCodec
+	Limits PageLimits
+}
+
+// Size returns the number of documents of the page of the pagination
+func (p Paginator) Size(pagination Pagination) int {
+	if p.Limits == (PageLimits{}) {
+		return DefaultPageLimits.Size(pagination)
+	}
+
+	return p.Limits.Size(pagination)
+}
+
+// FetchSize returns the number of documents to fetch, one more than the page size to know if there are more
+func (p Paginator) FetchSize(pagination Pagination) int {
+	return p.Size(pagination) + 1
+}
+
+// Page creates the page of the documents fetched with FetchSize. items must be a pointer to the slice of documents,
+// the extra document is removed from it, and when paginating with Before the documents are sorted back in the
+// order of the list. cursorOf returns the cursor of an element of the slice
+func (p Paginator) Page(items interface{}, pagination Pagination, cursorOf func(item interface{}) Cursor) (Page, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return Page{}, fmt.Errorf("items must be a pointer to a slice, got %T", items)
+	}
+
+	s := v.Elem()
+	page := Page{}
+	if size := p.Size(pagination); s.Len() > size {
+		page.HasMore = true
+		s = s.Slice(0, size)
+	}
+
+	backwards := pagination.Before != ""
+	if backwards {
+		swap := reflect.Swapper(s.Interface())
+		for i, j := 0, s.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+	v.Elem().Set(s)
+	page.Items = s.Interface()
+
+	if page.HasMore && s.Len() > 0 {
+		edge := s.Index(s.Len() - 1)
+		if backwards {
+			edge = s.Index(0)
+		}
+		cursor, err := p.Cursors().Encode(cursorOf(edge.Interface()))
+		if err != nil {
+			return Page{}, err
+		}
+		page.NextCursor = cursor
+	}
+
+	return page, nil
+}
+
+// Cursors returns the codec of the cursors, one that doesn't sign them if Codec is nil
+func (p Paginator) Cursors() *CursorCodec {
+	if p.Codec == nil {
+		return NewCursorCodec(nil)
+	}
+
+	return p.Codec
+}
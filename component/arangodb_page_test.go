@@ -0,0 +1,78 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPageLimitsSize(t *testing.T) {
+	limits := PageLimits{Default: 20, Max: 50}
+
+	assert.Equal(t, 20, limits.Size(Pagination{}))
+	assert.Equal(t, 10, limits.Size(Pagination{Limit: 10}))
+	assert.Equal(t, 50, limits.Size(Pagination{Limit: 500}))
+	assert.Equal(t, 500, PageLimits{Default: 20}.Size(Pagination{Limit: 500}))
+	assert.Equal(t, 21, Paginator{}.FetchSize(Pagination{}))
+}
+
+func TestPaginatorPage(t *testing.T) {
+	paginator := Paginator{Codec: NewCursorCodec(nil), Limits: PageLimits{Default: 2, Max: 10}}
+	cursorOf := func(item interface{}) Cursor {
+		return NewCursor(item.(string))
+	}
+	cursor := func(key string) string {
+		c, _ := paginator.Codec.Encode(NewCursor(key))
+		return c
+	}
+
+	testCases := map[string]struct {
+		items          []string
+		pagination     Pagination
+		expectedItems  []string
+		expectedCursor string
+		expectedMore   bool
+	}{
+		"if there is an extra item it should be removed and there should be more": {
+			items:          []string{"a", "b", "c"},
+			expectedItems:  []string{"a", "b"},
+			expectedCursor: cursor("b"),
+			expectedMore:   true,
+		},
+		"if there isn't an extra item there shouldn't be more": {
+			items:         []string{"a", "b"},
+			expectedItems: []string{"a", "b"},
+		},
+		"if it's paginating backwards the items should be reversed": {
+			items:          []string{"c", "b", "a"},
+			pagination:     Pagination{Before: "d"},
+			expectedItems:  []string{"b", "c"},
+			expectedCursor: cursor("b"),
+			expectedMore:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			items := tc.items
+			page, err := paginator.Page(&items, tc.pagination, cursorOf)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedItems, items)
+			assert.Equal(t, tc.expectedItems, page.Items)
+			assert.Equal(t, tc.expectedCursor, page.NextCursor)
+			assert.Equal(t, tc.expectedMore, page.HasMore)
+		})
+	}
+
+	_, err := paginator.Page([]string{}, Pagination{}, cursorOf)
+	assert.EqualError(t, err, "items must be a pointer to a slice, got []string")
+}
+
+func TestArangoPaginator(t *testing.T) {
+	a := NewArango("test", nil, "", "", OptionPageLimits(PageLimits{Default: 5, Max: 10}))
+	bindVars, err := a.EnhanceBindVarsWithIdTimeCursor(map[string]interface{}{}, Pagination{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, bindVars["paginationLimit"])
+	assert.Equal(t, DefaultPageLimits, (&Arango{}).Paginator().Limits)
+}
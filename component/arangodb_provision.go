@@ -0,0 +1,97 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+)
+
+// DBUser is a user of the database with its access level, which is required, Collections overrides the access level
+// of the database for specific collections
+type DBUser struct {
+	Name        string
+	Password    string
+	Access      driver.Grant
+	Collections map[string]driver.Grant
+}
+
+// OptionArangoSkipCreate connects to an existing database instead of creating it, so the user only needs
+// access to the database. The database and the user are created beforehand with Provision
+func OptionArangoSkipCreate() OptionArango {
+	return func(a *Arango) {
+		a.skipCreate = true
+	}
+}
+
+// Provision creates the database if it doesn't exist, and the users with their access levels, it must be called
+// with an administrator user from a provisioning step instead of at runtime. The users that exist get their password
+// updated. It can be called again after the collections are created to grant the access to them, since the
+// collections of the grants must exist. Every user must have an access level
+func (a *Arango) Provision(ctx context.Context, users []DBUser) error {
+	client, err := a.ensureClient()
+	if err != nil {
+		return err
+	}
+
+	return a.provision(ctx, client, users)
+}
+
+func (a *Arango) provision(ctx context.Context, client databaseClient, users []DBUser) error {
+	for _, u := range users {
+		if u.Access == "" {
+			return fmt.Errorf("missing access level of user '%s'", u.Name)
+		}
+	}
+
+	if a.clientDB == nil {
+		db, err := client.CreateDatabase(ctx, a.db, nil)
+		if a.clientDB, err = a.processCreateDBError(ctx, client, db, err); err != nil {
+			return err
+		}
+	}
+
+	for _, u := range users {
+		if err := a.provisionUser(ctx, client, u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Arango) provisionUser(ctx context.Context, client databaseClient, u DBUser) error {
+	active := true
+	opts := driver.UserOptions{Password: u.Password, Active: &active}
+	exists, err := client.UserExists(ctx, u.Name)
+	if err != nil {
+		return fmt.Errorf("unable to check user '%s': %w", u.Name, err)
+	}
+
+	var user driver.User
+	if exists {
+		if user, err = client.User(ctx, u.Name); err != nil {
+			return fmt.Errorf("unable to get user '%s': %w", u.Name, err)
+		}
+		if err := user.Update(ctx, opts); err != nil {
+			return fmt.Errorf("unable to update user '%s': %w", u.Name, err)
+		}
+	} else if user, err = client.CreateUser(ctx, u.Name, &opts); err != nil {
+		return fmt.Errorf("unable to create user '%s': %w", u.Name, err)
+	}
+
+	if err := user.SetDatabaseAccess(ctx, a.clientDB, u.Access); err != nil {
+		return fmt.Errorf("unable to grant access to user '%s': %w", u.Name, err)
+	}
+
+	for name, grant := range u.Collections {
+		c, err := a.clientDB.Collection(ctx, name)
+		if err != nil {
+			return fmt.Errorf("unable to get collection '%s': %w", name, err)
+		}
+		if err := user.SetCollectionAccess(ctx, c, grant); err != nil {
+			return fmt.Errorf("unable to grant access to collection '%s' to user '%s': %w", name, u.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package component
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"sync"
+)
+
+var (
+	lockdatabaseClientMockCreateDatabase sync.RWMutex
+	lockdatabaseClientMockCreateUser     sync.RWMutex
+	lockdatabaseClientMockDatabase       sync.RWMutex
+	lockdatabaseClientMockUser           sync.RWMutex
+	lockdatabaseClientMockUserExists     sync.RWMutex
+)
+
+// Ensure, that databaseClientMock does implement databaseClient.
+// If this is not the case, regenerate this file with moq.
+var _ databaseClient = &databaseClientMock{}
+
+// databaseClientMock is a mock implementation of databaseClient.
+//
+//     func TestSomethingThatUsesdatabaseClient(t *testing.T) {
+//
+//         // make and configure a mocked databaseClient
+//         mockeddatabaseClient := &databaseClientMock{
+//             CreateDatabaseFunc: func(ctx context.Context, name string, options *driver.CreateDatabaseOptions) (driver.Database, error) {
+// 	               panic("mock out the CreateDatabase method")
+//             },
+//             CreateUserFunc: func(ctx context.Context, name string, options *driver.UserOptions) (driver.User, error) {
+// 	               panic("mock out the CreateUser method")
+//             },
+//             DatabaseFunc: func(ctx context.Context, name string) (driver.Database, error) {
+// 	               panic("mock out the Database method")
+//             },
+//             UserFunc: func(ctx context.Context, name string) (driver.User, error) {
+// 	               panic("mock out the User method")
+//             },
+//             UserExistsFunc: func(ctx context.Context, name string) (bool, error) {
+// 	               panic("mock out the UserExists method")
+//             },
+//         }
+//
+//         // use mockeddatabaseClient in code that requires databaseClient
+//         // and then make assertions.
+//
+//     }
+type databaseClientMock struct {
+	// CreateDatabaseFunc mocks the CreateDatabase method.
+	CreateDatabaseFunc func(ctx context.Context, name string, options *driver.CreateDatabaseOptions) (driver.Database, error)
+
+	// CreateUserFunc mocks the CreateUser method.
+	CreateUserFunc func(ctx context.Context, name string, options *driver.UserOptions) (driver.User, error)
+
+	// DatabaseFunc mocks the Database method.
+	DatabaseFunc func(ctx context.Context, name string) (driver.Database, error)
+
+	// UserFunc mocks the User method.
+	UserFunc func(ctx context.Context, name string) (driver.User, error)
+
+	// UserExistsFunc mocks the UserExists method.
+	UserExistsFunc func(ctx context.Context, name string) (bool, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CreateDatabase holds details about calls to the CreateDatabase method.
+		CreateDatabase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+			// Options is the options argument value.
+			Options *driver.CreateDatabaseOptions
+		}
+		// CreateUser holds details about calls to the CreateUser method.
+		CreateUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+			// Options is the options argument value.
+			Options *driver.UserOptions
+		}
+		// Database holds details about calls to the Database method.
+		Database []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+		// User holds details about calls to the User method.
+		User []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+		// UserExists holds details about calls to the UserExists method.
+		UserExists []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+	}
+}
+
+// CreateDatabase calls CreateDatabaseFunc.
+func (mock *databaseClientMock) CreateDatabase(ctx context.Context, name string, options *driver.CreateDatabaseOptions) (driver.Database, error) {
+	if mock.CreateDatabaseFunc == nil {
+		panic("databaseClientMock.CreateDatabaseFunc: method is nil but databaseClient.CreateDatabase was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Name    string
+		Options *driver.CreateDatabaseOptions
+	}{
+		Ctx:     ctx,
+		Name:    name,
+		Options: options,
+	}
+	lockdatabaseClientMockCreateDatabase.Lock()
+	mock.calls.CreateDatabase = append(mock.calls.CreateDatabase, callInfo)
+	lockdatabaseClientMockCreateDatabase.Unlock()
+	return mock.CreateDatabaseFunc(ctx, name, options)
+}
+
+// CreateDatabaseCalls gets all the calls that were made to CreateDatabase.
+// Check the length with:
+//     len(mockeddatabaseClient.CreateDatabaseCalls())
+func (mock *databaseClientMock) CreateDatabaseCalls() []struct {
+	Ctx     context.Context
+	Name    string
+	Options *driver.CreateDatabaseOptions
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Name    string
+		Options *driver.CreateDatabaseOptions
+	}
+	lockdatabaseClientMockCreateDatabase.RLock()
+	calls = mock.calls.CreateDatabase
+	lockdatabaseClientMockCreateDatabase.RUnlock()
+	return calls
+}
+
+// CreateUser calls CreateUserFunc.
+func (mock *databaseClientMock) CreateUser(ctx context.Context, name string, options *driver.UserOptions) (driver.User, error) {
+	if mock.CreateUserFunc == nil {
+		panic("databaseClientMock.CreateUserFunc: method is nil but databaseClient.CreateUser was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Name    string
+		Options *driver.UserOptions
+	}{
+		Ctx:     ctx,
+		Name:    name,
+		Options: options,
+	}
+	lockdatabaseClientMockCreateUser.Lock()
+	mock.calls.CreateUser = append(mock.calls.CreateUser, callInfo)
+	lockdatabaseClientMockCreateUser.Unlock()
+	return mock.CreateUserFunc(ctx, name, options)
+}
+
+// CreateUserCalls gets all the calls that were made to CreateUser.
+// Check the length with:
+//     len(mockeddatabaseClient.CreateUserCalls())
+func (mock *databaseClientMock) CreateUserCalls() []struct {
+	Ctx     context.Context
+	Name    string
+	Options *driver.UserOptions
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Name    string
+		Options *driver.UserOptions
+	}
+	lockdatabaseClientMockCreateUser.RLock()
+	calls = mock.calls.CreateUser
+	lockdatabaseClientMockCreateUser.RUnlock()
+	return calls
+}
+
+// Database calls DatabaseFunc.
+func (mock *databaseClientMock) Database(ctx context.Context, name string) (driver.Database, error) {
+	if mock.DatabaseFunc == nil {
+		panic("databaseClientMock.DatabaseFunc: method is nil but databaseClient.Database was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	lockdatabaseClientMockDatabase.Lock()
+	mock.calls.Database = append(mock.calls.Database, callInfo)
+	lockdatabaseClientMockDatabase.Unlock()
+	return mock.DatabaseFunc(ctx, name)
+}
+
+// DatabaseCalls gets all the calls that were made to Database.
+// Check the length with:
+//     len(mockeddatabaseClient.DatabaseCalls())
+func (mock *databaseClientMock) DatabaseCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	lockdatabaseClientMockDatabase.RLock()
+	calls = mock.calls.Database
+	lockdatabaseClientMockDatabase.RUnlock()
+	return calls
+}
+
+// User calls UserFunc.
+func (mock *databaseClientMock) User(ctx context.Context, name string) (driver.User, error) {
+	if mock.UserFunc == nil {
+		panic("databaseClientMock.UserFunc: method is nil but databaseClient.User was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	lockdatabaseClientMockUser.Lock()
+	mock.calls.User = append(mock.calls.User, callInfo)
+	lockdatabaseClientMockUser.Unlock()
+	return mock.UserFunc(ctx, name)
+}
+
+// UserCalls gets all the calls that were made to User.
+// Check the length with:
+//     len(mockeddatabaseClient.UserCalls())
+func (mock *databaseClientMock) UserCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	lockdatabaseClientMockUser.RLock()
+	calls = mock.calls.User
+	lockdatabaseClientMockUser.RUnlock()
+	return calls
+}
+
+// UserExists calls UserExistsFunc.
+func (mock *databaseClientMock) UserExists(ctx context.Context, name string) (bool, error) {
+	if mock.UserExistsFunc == nil {
+		panic("databaseClientMock.UserExistsFunc: method is nil but databaseClient.UserExists was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	lockdatabaseClientMockUserExists.Lock()
+	mock.calls.UserExists = append(mock.calls.UserExists, callInfo)
+	lockdatabaseClientMockUserExists.Unlock()
+	return mock.UserExistsFunc(ctx, name)
+}
+
+// UserExistsCalls gets all the calls that were made to UserExists.
+// Check the length with:
+//     len(mockeddatabaseClient.UserExistsCalls())
+func (mock *databaseClientMock) UserExistsCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	lockdatabaseClientMockUserExists.RLock()
+	calls = mock.calls.UserExists
+	lockdatabaseClientMockUserExists.RUnlock()
+	return calls
+}
@@ -0,0 +1,146 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newDatabaseClientMock(user *userMock, exists bool, createDBErr, createUserErr error) *databaseClientMock {
+	return &databaseClientMock{
+		CreateDatabaseFunc: func(ctx context.Context, name string, options *driver.CreateDatabaseOptions) (driver.Database, error) {
+			return nil, createDBErr
+		},
+		DatabaseFunc: func(ctx context.Context, name string) (driver.Database, error) {
+			return nil, nil
+		},
+		UserExistsFunc: func(ctx context.Context, name string) (bool, error) {
+			return exists, nil
+		},
+		UserFunc: func(ctx context.Context, name string) (driver.User, error) {
+			return user, nil
+		},
+		CreateUserFunc: func(ctx context.Context, name string, options *driver.UserOptions) (driver.User, error) {
+			return user, createUserErr
+		},
+	}
+}
+
+func newUserMock() *userMock {
+	return &userMock{
+		UpdateFunc: func(ctx context.Context, options driver.UserOptions) error {
+			return nil
+		},
+		SetDatabaseAccessFunc: func(ctx context.Context, db driver.Database, access driver.Grant) error {
+			return nil
+		},
+	}
+}
+
+func TestProvision(t *testing.T) {
+	testCases := map[string]struct {
+		users         []DBUser
+		exists        bool
+		createDBErr   error
+		createUserErr error
+		expectedErr   bool
+		databaseCalls int
+		createCalls   int
+		updateCalls   int
+		access        []driver.Grant
+	}{
+		"a new user should be created with its access level": {
+			users:       []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadOnly}},
+			createCalls: 1,
+			access:      []driver.Grant{driver.GrantReadOnly},
+		},
+		"an existing user should get its password updated": {
+			users:       []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadWrite}},
+			exists:      true,
+			updateCalls: 1,
+			access:      []driver.Grant{driver.GrantReadWrite},
+		},
+		"if the database exists it should be opened": {
+			users:         []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadWrite}},
+			createDBErr:   driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrDuplicate},
+			databaseCalls: 1,
+			createCalls:   1,
+			access:        []driver.Grant{driver.GrantReadWrite},
+		},
+		"a user without access level should be rejected before the database is created": {
+			users:       []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadWrite}, {Name: "other"}},
+			expectedErr: true,
+		},
+		"if the database can't be created the users shouldn't be created": {
+			users:       []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadWrite}},
+			createDBErr: errors.New("forbidden"),
+			expectedErr: true,
+		},
+		"if the user can't be created the access shouldn't be granted": {
+			users:         []DBUser{{Name: "app", Password: "pass", Access: driver.GrantReadWrite}},
+			createUserErr: errors.New("forbidden"),
+			expectedErr:   true,
+			createCalls:   1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			user := newUserMock()
+			client := newDatabaseClientMock(user, tc.exists, tc.createDBErr, tc.createUserErr)
+			a := NewArango("test", nil, "admin", "admin")
+
+			err := a.provision(context.Background(), client, tc.users)
+
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Len(t, client.DatabaseCalls(), tc.databaseCalls)
+			assert.Len(t, client.CreateUserCalls(), tc.createCalls)
+			assert.Len(t, user.UpdateCalls(), tc.updateCalls)
+			for _, c := range client.CreateUserCalls() {
+				assert.Equal(t, "pass", c.Options.Password)
+				assert.True(t, *c.Options.Active)
+			}
+			for _, c := range user.UpdateCalls() {
+				assert.Equal(t, "pass", c.Options.Password)
+			}
+			access := make([]driver.Grant, 0)
+			for _, c := range user.SetDatabaseAccessCalls() {
+				access = append(access, c.Access)
+			}
+			assert.Equal(t, append([]driver.Grant{}, tc.access...), access)
+		})
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	testCases := map[string]struct {
+		options     []OptionArango
+		createCalls int
+		openCalls   int
+	}{
+		"by default the database should be created with the user": {
+			createCalls: 1,
+		},
+		"with OptionArangoSkipCreate the existing database should be opened": {
+			options:   []OptionArango{OptionArangoSkipCreate()},
+			openCalls: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newDatabaseClientMock(nil, false, nil, nil)
+			a := NewArango("test", nil, "app", "pass", tc.options...)
+
+			assert.NoError(t, a.openDB(context.Background(), client))
+			assert.Len(t, client.CreateDatabaseCalls(), tc.createCalls)
+			assert.Len(t, client.DatabaseCalls(), tc.openCalls)
+			for _, c := range client.CreateDatabaseCalls() {
+				assert.Equal(t, "test", c.Name)
+				assert.Equal(t, "app", c.Options.Users[0].UserName)
+			}
+		})
+	}
+}
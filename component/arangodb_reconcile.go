@@ -0,0 +1,324 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type IndexChange string
+
+const (
+	IndexCreate   IndexChange = "create"
+	IndexDrop     IndexChange = "drop"
+	IndexRecreate IndexChange = "recreate"
+)
+
+// IndexInfo is the definition of an index as it is stored in the database
+type IndexInfo struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Fields      IndexFields `json:"fields"`
+	Unique      bool        `json:"unique"`
+	Sparse      bool        `json:"sparse"`
+	GeoJSON     bool        `json:"geoJson"`
+	MinLength   int         `json:"minLength"`
+	ExpireAfter int         `json:"expireAfter"`
+}
+
+// IndexFields are the names of the fields of an index, inverted indexes return them as objects
+// with the name inside, only the name is kept
+type IndexFields []string
+
+func (f *IndexFields) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := make(IndexFields, 0, len(raw))
+	for _, r := range raw {
+		var name string
+		if err := json.Unmarshal(r, &name); err == nil {
+			fields = append(fields, name)
+			continue
+		}
+
+		var field struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(r, &field); err != nil {
+			return fmt.Errorf("unable to parse index field: %w", err)
+		}
+		fields = append(fields, field.Name)
+	}
+	*f = fields
+
+	return nil
+}
+
+// IndexDiff is a difference between the declared indexes of a collection and the existing ones
+type IndexDiff struct {
+	Collection string
+	Index      string
+	Change     IndexChange
+	Reason     string
+}
+
+func (d IndexDiff) String() string {
+	return fmt.Sprintf("%s index '%s' in '%s': %s", d.Change, d.Index, d.Collection, d.Reason)
+}
+
+// ReconcileOptions tells ReconcileIndexes which of the differences found should be applied,
+// with the zero value the differences are only reported
+type ReconcileOptions struct {
+	// CreateMissing creates the declared indexes that don't exist
+	CreateMissing bool
+	// RecreateMismatched drops and creates again the indexes whose definition changed
+	RecreateMismatched bool
+	// DropStale drops the indexes that exist but aren't declared anymore
+	DropStale bool
+}
+
+// ListIndexes returns the indexes of the collection, including the ones created by the system (primary and edge)
+func (a *Arango) ListIndexes(ctx context.Context, collection string) ([]IndexInfo, error) {
+	var data struct {
+		Indexes []IndexInfo `json:"indexes"`
+	}
+	if err := a.rawRequest(ctx, "GET", path.Join("_api", "index"), map[string]string{"collection": collection},
+		nil, &data, 200); err != nil {
+		return nil, fmt.Errorf("unable to list indexes of '%s': %w", collection, err)
+	}
+
+	return data.Indexes, nil
+}
+
+// DiffIndexes compares the declared indexes of a collection against the existing ones,
+// the indexes are matched by name
+func (a *Arango) DiffIndexes(ctx context.Context, collection string, indexes []Index) ([]IndexDiff, error) {
+	existing, err := a.ListIndexes(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffIndexes(collection, indexes, existing)
+}
+
+// DiffSchema compares the declared indexes of every collection and edge against the existing ones,
+// the differences are sorted by collection and index
+func (a *Arango) DiffSchema(ctx context.Context, collections []Collection, edges []Edge) ([]IndexDiff, error) {
+	diffs := make([]IndexDiff, 0)
+	for name, indexes := range schemaIndexes(collections, edges) {
+		d, err := a.DiffIndexes(ctx, name, indexes)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, d...)
+	}
+	sortIndexDiffs(diffs)
+
+	return diffs, nil
+}
+
+func sortIndexDiffs(diffs []IndexDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].Collection != diffs[j].Collection {
+			return diffs[i].Collection < diffs[j].Collection
+		}
+		return diffs[i].Index < diffs[j].Index
+	})
+}
+
+// ReconcileIndexes computes the differences between the declared collections and edges and the existing ones,
+// applies the ones enabled in the options, and returns all of them
+func (a *Arango) ReconcileIndexes(ctx context.Context, collections []Collection, edges []Edge, opts ReconcileOptions) ([]IndexDiff, error) {
+	declared := schemaIndexes(collections, edges)
+	diffs, err := a.DiffSchema(ctx, collections, edges)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range diffs {
+		apply := (d.Change == IndexCreate && opts.CreateMissing) ||
+			(d.Change == IndexRecreate && opts.RecreateMismatched) ||
+			(d.Change == IndexDrop && opts.DropStale)
+		if !apply {
+			continue
+		}
+
+		c, err := a.clientDB.Collection(ctx, d.Collection)
+		if err != nil {
+			return diffs, fmt.Errorf("unable to get collection '%s': %w", d.Collection, err)
+		}
+
+		if d.Change != IndexCreate {
+			if err := a.dropIndex(ctx, c, d.Index); err != nil {
+				return diffs, err
+			}
+		}
+
+		if d.Change != IndexDrop {
+			for _, ix := range declared[d.Collection] {
+				if ix.Name() != d.Index {
+					continue
+				}
+				if err := a.ensureIndexes(ctx, c, []Index{ix}); err != nil {
+					return diffs, err
+				}
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+func (a *Arango) dropIndex(ctx context.Context, c driver.Collection, name string) error {
+	ix, err := c.Index(ctx, name)
+	if err != nil {
+		return fmt.Errorf("unable to get index '%s': %w", name, err)
+	}
+	if err := ix.Remove(ctx); err != nil {
+		return fmt.Errorf("unable to drop index '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+func schemaIndexes(collections []Collection, edges []Edge) map[string][]Index {
+	indexes := make(map[string][]Index, len(collections)+len(edges))
+	for _, c := range collections {
+		indexes[c.Name] = c.Indexes
+	}
+	for _, e := range edges {
+		indexes[e.Name] = e.Indexes
+	}
+
+	return indexes
+}
+
+func diffIndexes(collection string, declared []Index, existing []IndexInfo) ([]IndexDiff, error) {
+	byName := make(map[string]IndexInfo, len(existing))
+	for _, info := range existing {
+		byName[info.Name] = info
+	}
+
+	diffs := make([]IndexDiff, 0)
+	seen := make(map[string]bool, len(declared))
+	for _, ix := range declared {
+		seen[ix.Name()] = true
+		want, err := indexInfo(ix)
+		if err != nil {
+			return nil, err
+		}
+
+		got, ok := byName[ix.Name()]
+		if !ok {
+			diffs = append(diffs, IndexDiff{Collection: collection, Index: ix.Name(), Change: IndexCreate, Reason: "missing"})
+			continue
+		}
+
+		if reason := compareIndexInfo(want, got); reason != "" {
+			diffs = append(diffs, IndexDiff{Collection: collection, Index: ix.Name(), Change: IndexRecreate, Reason: reason})
+		}
+	}
+
+	for _, info := range existing {
+		if seen[info.Name] || isSystemIndex(info) {
+			continue
+		}
+		diffs = append(diffs, IndexDiff{Collection: collection, Index: info.Name, Change: IndexDrop, Reason: "not declared"})
+	}
+
+	return diffs, nil
+}
+
+// indexInfo returns the definition the database will store for the declared index
+func indexInfo(ix Index) (IndexInfo, error) {
+	info := IndexInfo{Name: ix.Name(), Fields: ix.Fields()}
+	switch index := ix.(type) {
+	case GeoIndex:
+		info.Type = string(driver.GeoIndex)
+		info.GeoJSON = index.GeoJson
+		info.Sparse = true
+	case HashIndex:
+		info.Type = string(driver.HashIndex)
+		info.Unique = index.Unique
+		info.Sparse = index.Sparse
+	case PersistentIndex:
+		info.Type = string(driver.PersistentIndex)
+		info.Unique = index.Unique
+		info.Sparse = index.Sparse
+	case SkipListIndex:
+		info.Type = string(driver.SkipListIndex)
+		info.Unique = index.Unique
+		info.Sparse = index.Sparse
+	case ZKDIndex:
+		info.Type = "zkd"
+		info.Unique = index.Unique
+	case InvertedIndex:
+		info.Type = "inverted"
+	case TTLIndex:
+		info.Type = string(driver.TTLIndex)
+		info.ExpireAfter = index.ExpireAfter
+		info.Sparse = true
+	case FullTextIndex:
+		info.Type = string(driver.FullTextIndex)
+		info.MinLength = index.MinLength
+		info.Sparse = true
+	default:
+		return IndexInfo{}, fmt.Errorf("unhandled index type")
+	}
+
+	return info, nil
+}
+
+// compareIndexInfo returns why both definitions differ, or an empty string if they are the same
+func compareIndexInfo(want, got IndexInfo) string {
+	var reasons []string
+	if normalizeIndexType(want.Type) != normalizeIndexType(got.Type) {
+		reasons = append(reasons, fmt.Sprintf("type %s != %s", got.Type, want.Type))
+	}
+	if !reflect.DeepEqual(want.Fields, got.Fields) {
+		reasons = append(reasons, fmt.Sprintf("fields %v != %v", got.Fields, want.Fields))
+	}
+	if want.Unique != got.Unique {
+		reasons = append(reasons, fmt.Sprintf("unique %t != %t", got.Unique, want.Unique))
+	}
+	if want.Sparse != got.Sparse {
+		reasons = append(reasons, fmt.Sprintf("sparse %t != %t", got.Sparse, want.Sparse))
+	}
+	if want.GeoJSON != got.GeoJSON {
+		reasons = append(reasons, fmt.Sprintf("geoJson %t != %t", got.GeoJSON, want.GeoJSON))
+	}
+	if want.MinLength != got.MinLength {
+		reasons = append(reasons, fmt.Sprintf("minLength %d != %d", got.MinLength, want.MinLength))
+	}
+	if want.ExpireAfter != got.ExpireAfter {
+		reasons = append(reasons, fmt.Sprintf("expireAfter %d != %d", got.ExpireAfter, want.ExpireAfter))
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// normalizeIndexType maps the aliases the server uses for the same kind of index,
+// hash and skiplist indexes are persistent ones in RocksDB and geo indexes may be reported as geo1/geo2
+func normalizeIndexType(t string) string {
+	switch t {
+	case string(driver.HashIndex), string(driver.SkipListIndex):
+		return string(driver.PersistentIndex)
+	case "geo1", "geo2":
+		return string(driver.GeoIndex)
+	default:
+		return t
+	}
+}
+
+func isSystemIndex(info IndexInfo) bool {
+	return info.Type == string(driver.PrimaryIndex) || info.Type == string(driver.EdgeIndex)
+}
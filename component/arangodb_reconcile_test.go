@@ -0,0 +1,97 @@
+package component
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffIndexes(t *testing.T) {
+	primary := IndexInfo{Name: "primary", Type: "primary", Fields: []string{"_key"}, Unique: true}
+	testCases := map[string]struct {
+		declared      []Index
+		existing      []IndexInfo
+		expectedDiffs []IndexDiff
+	}{
+		"if the indexes are the same there should be no differences": {
+			declared: []Index{HashIndex{IxName: "ix_email", IxFields: []string{"email"}, Unique: true}},
+			existing: []IndexInfo{
+				primary,
+				{Name: "ix_email", Type: "hash", Fields: []string{"email"}, Unique: true},
+			},
+			expectedDiffs: []IndexDiff{},
+		},
+		"if the server reports a hash index as persistent there should be no differences": {
+			declared: []Index{HashIndex{IxName: "ix_email", IxFields: []string{"email"}}},
+			existing: []IndexInfo{
+				primary,
+				{Name: "ix_email", Type: "persistent", Fields: []string{"email"}},
+			},
+			expectedDiffs: []IndexDiff{},
+		},
+		"if a geo index is unchanged there should be no differences since the server reports it as sparse": {
+			declared: []Index{GeoIndex{IxName: "ix_location", IxFields: []string{"location"}, GeoJson: true}},
+			existing: []IndexInfo{
+				primary,
+				{Name: "ix_location", Type: "geo", Fields: []string{"location"}, Sparse: true, GeoJSON: true},
+			},
+			expectedDiffs: []IndexDiff{},
+		},
+		"if an index is missing it should be created": {
+			declared:      []Index{TTLIndex{IxName: "ix_ttl", IxField: "createdAt", ExpireAfter: 60}},
+			existing:      []IndexInfo{primary},
+			expectedDiffs: []IndexDiff{{Collection: "users", Index: "ix_ttl", Change: IndexCreate, Reason: "missing"}},
+		},
+		"if an index is not declared anymore it should be dropped": {
+			existing: []IndexInfo{
+				primary,
+				{Name: "ix_old", Type: "hash", Fields: []string{"old"}},
+			},
+			expectedDiffs: []IndexDiff{{Collection: "users", Index: "ix_old", Change: IndexDrop, Reason: "not declared"}},
+		},
+		"if an index changed its fields or uniqueness it should be recreated": {
+			declared: []Index{HashIndex{IxName: "ix_email", IxFields: []string{"email", "tenant"}, Unique: true}},
+			existing: []IndexInfo{
+				primary,
+				{Name: "ix_email", Type: "hash", Fields: []string{"email"}},
+			},
+			expectedDiffs: []IndexDiff{{
+				Collection: "users",
+				Index:      "ix_email",
+				Change:     IndexRecreate,
+				Reason:     "fields [email] != [email tenant], unique false != true",
+			}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := diffIndexes("users", tc.declared, tc.existing)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDiffs, diffs)
+		})
+	}
+}
+
+func TestIndexFieldsUnmarshal(t *testing.T) {
+	var info IndexInfo
+	err := json.Unmarshal([]byte(`{"name":"ix","type":"inverted","fields":["a",{"name":"b","analyzer":"text_en"}]}`), &info)
+	assert.NoError(t, err)
+	assert.Equal(t, IndexFields{"a", "b"}, info.Fields)
+}
+
+func TestSortIndexDiffs(t *testing.T) {
+	diffs := []IndexDiff{
+		{Collection: "users", Index: "email", Change: IndexDrop},
+		{Collection: "orders", Index: "user", Change: IndexCreate},
+		{Collection: "users", Index: "age", Change: IndexCreate},
+	}
+
+	sortIndexDiffs(diffs)
+
+	assert.Equal(t, []IndexDiff{
+		{Collection: "orders", Index: "user", Change: IndexCreate},
+		{Collection: "users", Index: "age", Change: IndexCreate},
+		{Collection: "users", Index: "email", Change: IndexDrop},
+	}, diffs)
+}
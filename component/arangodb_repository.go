@@ -0,0 +1,169 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"reflect"
+)
+
+var (
+	// ErrNotFound is returned when the document doesn't exist
+	ErrNotFound = errors.New("document not found")
+	// ErrAlreadyExists is returned when the key of the document or one of its unique indexes is already used
+	ErrAlreadyExists = errors.New("document already exists")
+	// ErrRevisionConflict is returned when the revision of the document doesn't match the stored one,
+	// or when the document has been modified by another transaction
+	ErrRevisionConflict = errors.New("document revision conflict")
+)
+
+// Document can be embedded in the structs stored with a Repository to map the metadata of the document
+type Document struct {
+	Key string `json:"_key,omitempty"`
+	ID  string `json:"_id,omitempty"`
+	Rev string `json:"_rev,omitempty"`
+}
+
+// Repository provides the CRUD operations of the documents of a collection, all the documents passed must be
+// pointers to the struct type given when creating it, and the ones written are refreshed with the stored version,
+// including its _key, _id and _rev. When the _rev of a document is set it's used to detect concurrent modifications
+type Repository struct {
+	store      DocumentStore
+	collection string
+	docType    reflect.Type
+}
+
+// NewRepository creates a repository for the documents of the collection, prototype is a value or a pointer
+// of the struct type of the documents. The store is usually Arango, or the in-memory store in the tests
+func NewRepository(store DocumentStore, collection string, prototype interface{}) *Repository {
+	t := reflect.TypeOf(prototype)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return &Repository{
+		store:      store,
+		collection: collection,
+		docType:    t,
+	}
+}
+
+// Collection returns the name of the collection of the repository
+func (r *Repository) Collection() string {
+	return r.collection
+}
+
+// Create stores the document, if its _key is empty the database generates one
+func (r *Repository) Create(ctx context.Context, doc interface{}) error {
+	if err := r.checkDocument(doc); err != nil {
+		return err
+	}
+
+	return r.store.CreateDocument(ctx, r.collection, doc)
+}
+
+// Get reads the document with the key into result
+func (r *Repository) Get(ctx context.Context, key string, result interface{}) error {
+	if err := r.checkDocument(result); err != nil {
+		return err
+	}
+
+	return r.store.ReadDocument(ctx, r.collection, key, result)
+}
+
+// Update merges the fields of the document into the stored one, the fields with their zero value
+// are only written if they aren't omitted when marshalling
+func (r *Repository) Update(ctx context.Context, doc interface{}) error {
+	if err := r.checkDocument(doc); err != nil {
+		return err
+	}
+
+	return r.store.UpdateDocument(ctx, r.collection, doc)
+}
+
+// Replace overwrites the stored document with the given one
+func (r *Repository) Replace(ctx context.Context, doc interface{}) error {
+	if err := r.checkDocument(doc); err != nil {
+		return err
+	}
+
+	return r.store.ReplaceDocument(ctx, r.collection, doc)
+}
+
+// Delete removes the document with the key, if rev isn't empty the document is only removed
+// if it's the stored revision
+func (r *Repository) Delete(ctx context.Context, key, rev string) error {
+	return r.store.RemoveDocument(ctx, r.collection, key, rev)
+}
+
+// Upsert creates the document if there isn't one with its _key, otherwise the stored one is updated,
+// documents without _key are always created
+func (r *Repository) Upsert(ctx context.Context, doc interface{}) error {
+	if err := r.checkDocument(doc); err != nil {
+		return err
+	}
+
+	return r.store.UpsertDocument(ctx, r.collection, doc)
+}
+
+// BatchCreate stores all the documents in a single request, docs is a slice of structs or pointers to structs.
+// If some of the documents can't be created the others are kept and the first error is returned
+func (r *Repository) BatchCreate(ctx context.Context, docs interface{}) error {
+	v := reflect.ValueOf(docs)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("documents of '%s' must be a slice, got %T", r.collection, docs)
+	}
+	if elem := v.Type().Elem(); elem != r.docType && elem != reflect.PtrTo(r.docType) {
+		return fmt.Errorf("documents of '%s' must be of type %s, got %T", r.collection, r.docType, docs)
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+
+	return r.store.CreateDocuments(ctx, r.collection, docs)
+}
+
+func (r *Repository) checkDocument(doc interface{}) error {
+	t := reflect.TypeOf(doc)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem() != r.docType || reflect.ValueOf(doc).IsNil() {
+		return fmt.Errorf("document of '%s' must be a non nil *%s, got %T", r.collection, r.docType, doc)
+	}
+
+	return nil
+}
+
+// TranslateError maps the errors returned by the database to ErrNotFound, ErrAlreadyExists and ErrRevisionConflict,
+// that can be checked with errors.Is while the original error can still be got with errors.As.
+// Other errors are returned unchanged
+func TranslateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case driver.IsArangoErrorWithErrorNum(err, ErrDocumentNotFound):
+		return &translatedError{kind: ErrNotFound, err: err}
+	case driver.IsArangoErrorWithErrorNum(err, ErrUniqueConstraintViolated):
+		return &translatedError{kind: ErrAlreadyExists, err: err}
+	case driver.IsPreconditionFailed(err) || driver.IsArangoErrorWithErrorNum(err, ErrConflict):
+		return &translatedError{kind: ErrRevisionConflict, err: err}
+	default:
+		return err
+	}
+}
+
+type translatedError struct {
+	kind error
+	err  error
+}
+
+func (e *translatedError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+func (e *translatedError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *translatedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,83 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type repositoryTestDoc struct {
+	Document
+	Name string `json:"name"`
+}
+
+var _ DocumentStore = &Arango{}
+
+func TestTranslateError(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected error
+	}{
+		"if the document doesn't exist it should be not found": {
+			err:      driver.ArangoError{HasError: true, Code: 404, ErrorNum: ErrDocumentNotFound},
+			expected: ErrNotFound,
+		},
+		"if a unique constraint is violated it should already exist": {
+			err:      driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrUniqueConstraintViolated},
+			expected: ErrAlreadyExists,
+		},
+		"if the revision doesn't match it should be a revision conflict": {
+			err:      driver.ArangoError{HasError: true, Code: 412, ErrorNum: ErrConflict},
+			expected: ErrRevisionConflict,
+		},
+		"if there is a write-write conflict it should be a revision conflict": {
+			err:      driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrConflict},
+			expected: ErrRevisionConflict,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(TranslateError(tc.err), tc.expected))
+		})
+	}
+
+	other := fmt.Errorf("test")
+	assert.Equal(t, other, TranslateError(other))
+	assert.NoError(t, TranslateError(nil))
+}
+
+func TestRepositoryCheckDocument(t *testing.T) {
+	r := NewRepository(nil, "users", repositoryTestDoc{})
+
+	assert.NoError(t, r.checkDocument(&repositoryTestDoc{}))
+	assert.EqualError(t, r.Update(context.Background(), repositoryTestDoc{}),
+		"document of 'users' must be a non nil *component.repositoryTestDoc, got component.repositoryTestDoc")
+
+	var nilDoc *repositoryTestDoc
+	assert.Error(t, r.checkDocument(nilDoc))
+	assert.Error(t, r.BatchCreate(context.Background(), []string{"a"}))
+	assert.NoError(t, r.BatchCreate(context.Background(), []*repositoryTestDoc{}))
+}
+
+func TestDocumentMeta(t *testing.T) {
+	key, rev, err := DocumentMeta(&repositoryTestDoc{Document: Document{Key: "1", Rev: "abc"}, Name: "test"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", key)
+	assert.Equal(t, "abc", rev)
+}
+
+func TestTranslateErrorKeepsCause(t *testing.T) {
+	cause := driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrConflict, ErrorMessage: "write-write conflict"}
+	err := fmt.Errorf("test: %w", TranslateError(cause))
+
+	var ae driver.ArangoError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, cause, ae)
+	assert.True(t, errors.Is(err, ErrRevisionConflict))
+	assert.EqualError(t, err, "test: document revision conflict: write-write conflict")
+}
@@ -0,0 +1,105 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// arangoRequest is a request received by arangoServer, the body is decoded as JSON
+type arangoRequest struct {
+	Method string
+	Path   string
+	Query  map[string]string
+	Body   map[string]interface{}
+}
+
+// arangoServer answers the API calls made when the schema is created, it keeps the views
+// so ViewExists and View can find them
+type arangoServer struct {
+	mutex    sync.Mutex
+	requests []arangoRequest
+	views    map[string]bool
+}
+
+// newTestArango returns an Arango connected to the database 'test' of a fake server
+func newTestArango(t *testing.T, views ...string) (*Arango, *arangoServer) {
+	s := &arangoServer{views: make(map[string]bool, len(views))}
+	for _, v := range views {
+		s.views[v] = true
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+
+	a := NewArango("test", []string{srv.URL}, "user", "pass", OptionArangoSkipCreate())
+	if err := a.InitDB(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.reset()
+
+	return a, s
+}
+
+func (s *arangoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	req := arangoRequest{Method: r.Method, Path: strings.TrimPrefix(r.URL.Path, "/_db/test/"), Query: map[string]string{}}
+	for k := range r.URL.Query() {
+		req.Query[k] = r.URL.Query().Get(k)
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req.Body)
+	s.requests = append(s.requests, req)
+
+	status, res := http.StatusOK, map[string]interface{}{}
+	switch {
+	case req.Path == "_api/database/current":
+		res["result"] = map[string]interface{}{"name": "test"}
+	case req.Path == "_api/index":
+		status = http.StatusCreated
+		res = map[string]interface{}{"id": req.Query["collection"] + "/1", "type": req.Body["type"], "name": req.Body["name"]}
+	case req.Path == "_api/import":
+		status = http.StatusCreated
+	case req.Path == "_api/analyzer":
+		status = http.StatusCreated
+		res = req.Body
+	case req.Path == "_api/view":
+		status = http.StatusCreated
+		s.views[req.Body["name"].(string)] = true
+	case strings.HasPrefix(req.Path, "_api/view/") && req.Method == http.MethodGet:
+		name := strings.TrimPrefix(req.Path, "_api/view/")
+		if !s.views[name] {
+			status = http.StatusNotFound
+			res = map[string]interface{}{"error": true, "code": http.StatusNotFound, "errorNum": 1203}
+			break
+		}
+		res = map[string]interface{}{"name": name, "type": "arangosearch"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (s *arangoServer) reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = nil
+}
+
+// calls returns the method and path of the requests received
+func (s *arangoServer) calls() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	calls := make([]string, 0, len(s.requests))
+	for _, r := range s.requests {
+		calls = append(calls, r.Method+" "+r.Path)
+	}
+
+	return calls
+}
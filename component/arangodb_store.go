@@ -0,0 +1,251 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"reflect"
+	"strings"
+)
+
+type EdgeDirection string
+
+const (
+	EdgeOutbound EdgeDirection = "OUTBOUND"
+	EdgeInbound  EdgeDirection = "INBOUND"
+	EdgeAny      EdgeDirection = "ANY"
+)
+
+// DocumentStore are the operations on collections, documents and graphs that don't need AQL, it's implemented
+// by Arango and by the in-memory store of the arangotest package so the code using it can be tested without
+// a database. The documents written are refreshed with the stored version, including its _key, _id and _rev,
+// and the errors can be checked with ErrNotFound, ErrAlreadyExists and ErrRevisionConflict
+type DocumentStore interface {
+	CreateCollections(ctx context.Context, collections []Collection) error
+	CreateGraph(ctx context.Context, graph *Graph) error
+	// CreateDocument stores the document, if its _key is empty one is generated
+	CreateDocument(ctx context.Context, collection string, doc interface{}) error
+	// CreateDocuments stores a slice of documents, if some of them fail the first error is returned
+	CreateDocuments(ctx context.Context, collection string, docs interface{}) error
+	ReadDocument(ctx context.Context, collection, key string, result interface{}) error
+	// UpdateDocument merges the document into the stored one with the same _key, if _rev is set it must match
+	UpdateDocument(ctx context.Context, collection string, doc interface{}) error
+	// ReplaceDocument overwrites the stored document with the same _key, if _rev is set it must match
+	ReplaceDocument(ctx context.Context, collection string, doc interface{}) error
+	// UpsertDocument creates the document or updates the one with the same _key
+	UpsertDocument(ctx context.Context, collection string, doc interface{}) error
+	// RemoveDocument removes the document with the key, if rev isn't empty it must match
+	RemoveDocument(ctx context.Context, collection, key, rev string) error
+	// Traverse reads into result, a pointer to a slice, the vertices reached from the start vertex _id
+	// following the edges between min and max depth, each vertex once
+	Traverse(ctx context.Context, start string, edges []string, direction EdgeDirection, min, max int, result interface{}) error
+}
+
+func (a *Arango) CreateDocument(ctx context.Context, collection string, doc interface{}) error {
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.CreateDocument(driver.WithReturnNew(ctx, doc), doc); err != nil {
+		return storeError(err, collection, "unable to create document")
+	}
+
+	return nil
+}
+
+func (a *Arango) CreateDocuments(ctx context.Context, collection string, docs interface{}) error {
+	if v := reflect.ValueOf(docs); v.Kind() != reflect.Slice {
+		return fmt.Errorf("documents of '%s' must be a slice, got %T", collection, docs)
+	} else if v.Len() == 0 {
+		return nil
+	}
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	_, errs, err := c.CreateDocuments(driver.WithReturnNew(ctx, docs), docs)
+	if err != nil {
+		return storeError(err, collection, "unable to create documents")
+	}
+	for i, e := range errs {
+		if e != nil {
+			return storeError(e, collection, fmt.Sprintf("unable to create document %d", i))
+		}
+	}
+
+	return nil
+}
+
+func (a *Arango) ReadDocument(ctx context.Context, collection, key string, result interface{}) error {
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.ReadDocument(ctx, key, result); err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to get document '%s'", key))
+	}
+
+	return nil
+}
+
+func (a *Arango) UpdateDocument(ctx context.Context, collection string, doc interface{}) error {
+	key, rev, err := DocumentMeta(doc)
+	if err != nil {
+		return err
+	}
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.UpdateDocument(driver.WithReturnNew(withRevision(ctx, rev), doc), key, doc); err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to update document '%s'", key))
+	}
+
+	return nil
+}
+
+func (a *Arango) ReplaceDocument(ctx context.Context, collection string, doc interface{}) error {
+	key, rev, err := DocumentMeta(doc)
+	if err != nil {
+		return err
+	}
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.ReplaceDocument(driver.WithReturnNew(withRevision(ctx, rev), doc), key, doc); err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to replace document '%s'", key))
+	}
+
+	return nil
+}
+
+func (a *Arango) UpsertDocument(ctx context.Context, collection string, doc interface{}) error {
+	key, _, err := DocumentMeta(doc)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return a.CreateDocument(ctx, collection, doc)
+	}
+
+	query := "UPSERT { _key: @key } INSERT @doc UPDATE UNSET(@doc, '_rev') IN @@collection RETURN NEW"
+	bindVars := map[string]interface{}{"key": key, "doc": doc, "@collection": collection}
+	cursor, err := a.clientDB.Query(ctx, query, bindVars)
+	if err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to upsert document '%s'", key))
+	}
+	defer cursor.Close()
+
+	if _, err := cursor.ReadDocument(ctx, doc); err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to read upserted document '%s'", key))
+	}
+
+	return nil
+}
+
+func (a *Arango) RemoveDocument(ctx context.Context, collection, key, rev string) error {
+	c, err := a.collection(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.RemoveDocument(withRevision(ctx, rev), key); err != nil {
+		return storeError(err, collection, fmt.Sprintf("unable to delete document '%s'", key))
+	}
+
+	return nil
+}
+
+func (a *Arango) Traverse(ctx context.Context, start string, edges []string, direction EdgeDirection, min, max int, result interface{}) error {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("result must be a pointer to a slice, got %T", result)
+	}
+
+	bindVars := map[string]interface{}{"start": start, "min": min, "max": max}
+	refs := make([]string, len(edges))
+	for i, e := range edges {
+		refs[i] = fmt.Sprintf("@@edges%d", i)
+		bindVars[refs[i][1:]] = e
+	}
+	query := fmt.Sprintf("FOR v IN @min..@max %s @start %s OPTIONS {bfs: true, uniqueVertices: 'global'} RETURN v",
+		direction, strings.Join(refs, ", "))
+
+	cursor, err := a.clientDB.Query(ctx, query, bindVars)
+	if err != nil {
+		return fmt.Errorf("unable to traverse from '%s': %w", start, err)
+	}
+	defer cursor.Close()
+
+	items := v.Elem()
+	for cursor.HasMore() {
+		item := reflect.New(items.Type().Elem())
+		if _, err := cursor.ReadDocument(ctx, item.Interface()); err != nil {
+			return fmt.Errorf("unable to read vertex: %w", err)
+		}
+		items = reflect.Append(items, item.Elem())
+	}
+	v.Elem().Set(items)
+
+	return nil
+}
+
+// collection returns the collection with the name, the collections are kept once they are got
+func (a *Arango) collection(ctx context.Context, name string) (driver.Collection, error) {
+	a.mutex.RLock()
+	c, ok := a.collections[name]
+	a.mutex.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	// the collection is fetched without the lock so the other collections aren't blocked by the request,
+	// if two goroutines fetch it at the same time both get the same collection
+	c, err := a.clientDB.Collection(ctx, name)
+	if err != nil {
+		return nil, storeError(err, name, "unable to get collection")
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.collections == nil {
+		a.collections = make(map[string]driver.Collection)
+	}
+	a.collections[name] = c
+
+	return c, nil
+}
+
+// DocumentMeta returns the _key and _rev of a document as they are marshalled,
+// so they can come from any field or embedded struct
+func DocumentMeta(doc interface{}) (string, string, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to marshal document: %w", err)
+	}
+	var meta Document
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return "", "", fmt.Errorf("unable to read document metadata: %w", err)
+	}
+
+	return meta.Key, meta.Rev, nil
+}
+
+func withRevision(ctx context.Context, rev string) context.Context {
+	if rev == "" {
+		return ctx
+	}
+
+	return driver.WithRevision(ctx, rev)
+}
+
+func storeError(err error, collection, msg string) error {
+	return fmt.Errorf("%s in '%s': %w", msg, collection, TranslateError(err))
+}
@@ -0,0 +1,31 @@
+package component
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestArangoCollection(t *testing.T) {
+	a, srv := newTestArango(t)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"users", "posts", "users", "posts"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			c, err := a.collection(context.Background(), name)
+			assert.NoError(t, err)
+			assert.Equal(t, name, c.Name())
+		}(name)
+	}
+	wg.Wait()
+	srv.reset()
+
+	c, err := a.collection(context.Background(), "users")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "users", c.Name())
+	assert.Empty(t, srv.calls())
+}
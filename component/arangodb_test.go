@@ -0,0 +1,207 @@
+package component
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSameVertexConstraints(t *testing.T) {
+	testCases := map[string]struct {
+		a, b     driver.VertexConstraints
+		expected bool
+	}{
+		"if the collections are the same in a different order they should be the same": {
+			a:        driver.VertexConstraints{From: []string{"users", "teams"}, To: []string{"posts"}},
+			b:        driver.VertexConstraints{From: []string{"teams", "users"}, To: []string{"posts"}},
+			expected: true,
+		},
+		"if a collection was added they should differ": {
+			a:        driver.VertexConstraints{From: []string{"users"}, To: []string{"posts"}},
+			b:        driver.VertexConstraints{From: []string{"users"}, To: []string{"posts", "comments"}},
+			expected: false,
+		},
+		"if a collection was replaced they should differ": {
+			a:        driver.VertexConstraints{From: []string{"users", "users"}, To: []string{"posts"}},
+			b:        driver.VertexConstraints{From: []string{"users", "teams"}, To: []string{"posts"}},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sameVertexConstraints(tc.a, tc.b))
+		})
+	}
+}
+
+func TestArangoConnectionOptions(t *testing.T) {
+	testCases := map[string]struct {
+		options   []OptionArango
+		endpoints []string
+	}{
+		"with HTTP/2, TLS and timeout it should create an HTTP connection": {
+			options: []OptionArango{
+				OptionArangoHTTP2(), OptionArangoTLS(&tls.Config{}), OptionArangoTimeout(time.Second), OptionArangoJWT(),
+			},
+			endpoints: []string{"https://localhost:8529"},
+		},
+		"with VelocyStream it should create a VST connection": {
+			options:   []OptionArango{OptionArangoVST(), OptionArangoEndpointSync(time.Minute)},
+			endpoints: []string{"vst://localhost:8529"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := NewArango("test", tc.endpoints, "user", "pass", tc.options...)
+			client, err := a.newClient(a.Endpoints())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.endpoints, client.Connection().Endpoints())
+			assert.NoError(t, a.Close())
+		})
+	}
+}
+
+func TestCreateCollection(t *testing.T) {
+	testCases := map[string]struct {
+		colType driver.CollectionType
+		options *CollectionOptions
+		body    map[string]interface{}
+		schema  map[string]interface{}
+	}{
+		"without options only the name and type should be sent": {
+			colType: driver.CollectionTypeDocument,
+			body:    map[string]interface{}{"name": "users", "type": float64(2)},
+		},
+		"the cluster and key options should be sent when the collection is created": {
+			colType: driver.CollectionTypeEdge,
+			options: &CollectionOptions{
+				NumberOfShards:    3,
+				ShardKeys:         []string{"tenant"},
+				ReplicationFactor: 2,
+				WriteConcern:      2,
+				KeyOptions:        &driver.CollectionKeyOptions{Type: driver.KeyGeneratorAutoIncrement},
+			},
+			body: map[string]interface{}{
+				"name":              "users",
+				"type":              float64(3),
+				"numberOfShards":    float64(3),
+				"shardKeys":         []interface{}{"tenant"},
+				"replicationFactor": float64(2),
+				"writeConcern":      float64(2),
+				"keyOptions":        map[string]interface{}{"type": "autoincrement"},
+			},
+		},
+		"the schema should be set after the collection is created": {
+			colType: driver.CollectionTypeDocument,
+			options: &CollectionOptions{
+				Schema: &CollectionSchema{Rule: map[string]interface{}{"type": "object"}, Level: "moderate"},
+			},
+			body:   map[string]interface{}{"name": "users", "type": float64(2)},
+			schema: map[string]interface{}{"rule": map[string]interface{}{"type": "object"}, "level": "moderate"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a, srv := newTestArango(t)
+
+			c, err := a.createCollection(context.Background(), "users", tc.colType, tc.options)
+
+			require.NoError(t, err)
+			assert.Equal(t, "users", c.Name())
+			assert.Equal(t, "POST _api/collection", srv.calls()[0])
+			assert.Equal(t, tc.body, srv.requests[0].Body)
+			if tc.schema == nil {
+				assert.Len(t, srv.requests, 1)
+				return
+			}
+			assert.Equal(t, []string{"POST _api/collection", "PUT _api/collection/users/properties"}, srv.calls())
+			assert.Equal(t, map[string]interface{}{"schema": tc.schema}, srv.requests[1].Body)
+		})
+	}
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	testCases := map[string]struct {
+		index Index
+		body  map[string]interface{}
+	}{
+		"a persistent index should be created with its uniqueness and sparseness": {
+			index: PersistentIndex{IxName: "by_email", IxFields: []string{"email"}, Unique: true, Sparse: true},
+			body: map[string]interface{}{
+				"type": "persistent", "name": "by_email", "fields": []interface{}{"email"},
+				"unique": true, "sparse": true, "inBackground": true,
+			},
+		},
+		"a skiplist index should be created with its uniqueness": {
+			index: SkipListIndex{IxName: "by_age", IxFields: []string{"age"}, Unique: true},
+			body: map[string]interface{}{
+				"type": "skiplist", "name": "by_age", "fields": []interface{}{"age"},
+				"unique": true, "sparse": false, "inBackground": true,
+			},
+		},
+		"a zkd index should be created with double values": {
+			index: ZKDIndex{IxName: "by_position", IxFields: []string{"x", "y"}},
+			body: map[string]interface{}{
+				"type": "zkd", "name": "by_position", "fields": []interface{}{"x", "y"},
+				"fieldValueTypes": "double", "unique": false, "inBackground": true,
+			},
+		},
+		"an inverted index should be created with its analyzer": {
+			index: InvertedIndex{IxName: "by_text", IxFields: []string{"text"}, Analyzer: "text_en"},
+			body: map[string]interface{}{
+				"type": "inverted", "name": "by_text", "fields": []interface{}{"text"},
+				"analyzer": "text_en", "inBackground": true,
+			},
+		},
+		"an inverted index without analyzer should use the default one": {
+			index: InvertedIndex{IxName: "by_text", IxFields: []string{"text"}},
+			body: map[string]interface{}{
+				"type": "inverted", "name": "by_text", "fields": []interface{}{"text"}, "inBackground": true,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a, srv := newTestArango(t)
+			c, err := a.createCollection(context.Background(), "users", driver.CollectionTypeDocument, nil)
+			require.NoError(t, err)
+			srv.reset()
+
+			err = a.ensureIndexes(context.Background(), c, []Index{tc.index})
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"POST _api/index"}, srv.calls())
+			assert.Equal(t, map[string]string{"collection": "users"}, srv.requests[0].Query)
+			assert.Equal(t, tc.body, srv.requests[0].Body)
+		})
+	}
+}
+
+func TestArangoCloseDuringChecks(t *testing.T) {
+	a, _ := newTestArango(t)
+	ctx := context.Background()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			_, _ = a.Version(ctx)
+			_, _ = a.ClusterHealth(ctx)
+			a.PingEndpoints(ctx)
+		}
+	}()
+
+	assert.NoError(t, a.Close())
+	<-done
+
+	_, err := a.Version(ctx)
+	assert.EqualError(t, err, "client not initialized")
+}
@@ -0,0 +1,145 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"time"
+)
+
+const transactionAbortTimeout = 5 * time.Second
+
+type OptionTransaction func(*transaction)
+
+type transaction struct {
+	retries     int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	lockTimeout time.Duration
+	waitForSync bool
+	exclusive   []string
+}
+
+type transactionKey struct{}
+
+// transactionDB is the part of driver.Database used to run the stream transactions
+type transactionDB interface {
+	BeginTransaction(ctx context.Context, cols driver.TransactionCollections, opts *driver.BeginTransactionOptions) (driver.TransactionID, error)
+	CommitTransaction(ctx context.Context, tid driver.TransactionID, opts *driver.CommitTransactionOptions) error
+	AbortTransaction(ctx context.Context, tid driver.TransactionID, opts *driver.AbortTransactionOptions) error
+}
+
+// OptionTransactionRetries sets how many times the transaction is retried after a write-write conflict, by default 3
+func OptionTransactionRetries(retries int) OptionTransaction {
+	return func(t *transaction) {
+		t.retries = retries
+	}
+}
+
+// OptionTransactionBackoff sets the wait before the first retry, it's doubled on every retry up to max
+func OptionTransactionBackoff(backoff, max time.Duration) OptionTransaction {
+	return func(t *transaction) {
+		t.backoff = backoff
+		t.maxBackoff = max
+	}
+}
+
+// OptionTransactionLockTimeout sets how long the transaction waits for the locks of the collections
+func OptionTransactionLockTimeout(timeout time.Duration) OptionTransaction {
+	return func(t *transaction) {
+		t.lockTimeout = timeout
+	}
+}
+
+// OptionTransactionWaitForSync makes the commit wait until the data is synced to disk
+func OptionTransactionWaitForSync() OptionTransaction {
+	return func(t *transaction) {
+		t.waitForSync = true
+	}
+}
+
+// OptionTransactionExclusive sets the collections locked exclusively by the transaction
+func OptionTransactionExclusive(collections ...string) OptionTransaction {
+	return func(t *transaction) {
+		t.exclusive = collections
+	}
+}
+
+// TransactionID returns the id of the transaction of the context, if it has one
+func TransactionID(ctx context.Context) (driver.TransactionID, bool) {
+	id, ok := ctx.Value(transactionKey{}).(driver.TransactionID)
+	return id, ok
+}
+
+// WithTransaction runs fn inside a stream transaction that reads and writes the collections, the context
+// passed to fn has the id of the transaction so the requests made with it, like the ones of a Repository,
+// are part of the transaction. The transaction is committed if fn succeeds and aborted if it fails or panics,
+// when it fails because of a write-write conflict fn is run again in a new transaction.
+// If the context already has a transaction fn is run inside it
+func (a *Arango) WithTransaction(ctx context.Context, readCols, writeCols []string, fn func(ctx context.Context) error, opts ...OptionTransaction) error {
+	return withTransaction(ctx, a.clientDB, readCols, writeCols, fn, opts...)
+}
+
+func withTransaction(ctx context.Context, db transactionDB, readCols, writeCols []string, fn func(ctx context.Context) error, opts ...OptionTransaction) error {
+	if _, ok := TransactionID(ctx); ok {
+		return fn(ctx)
+	}
+
+	t := &transaction{
+		retries:    3,
+		backoff:    50 * time.Millisecond,
+		maxBackoff: time.Second,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+	cols := driver.TransactionCollections{Read: readCols, Write: writeCols, Exclusive: t.exclusive}
+
+	return retryWithBackoff(ctx, t.retries, t.backoff, t.maxBackoff, IsWriteConflict, func() error {
+		return t.run(ctx, db, cols, fn)
+	})
+}
+
+func (t *transaction) run(ctx context.Context, db transactionDB, cols driver.TransactionCollections, fn func(ctx context.Context) error) error {
+	id, err := db.BeginTransaction(ctx, cols, &driver.BeginTransactionOptions{
+		WaitForSync: t.waitForSync,
+		LockTimeout: t.lockTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			t.abort(db, id)
+		}
+	}()
+
+	txCtx := context.WithValue(driver.WithTransactionID(ctx, id), transactionKey{}, id)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if err := db.CommitTransaction(ctx, id, nil); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", TranslateError(err))
+	}
+	committed = true
+
+	return nil
+}
+
+// abort uses its own context since the one of the transaction may be canceled, the transaction
+// is aborted by the server after its idle timeout if this fails
+func (t *transaction) abort(db transactionDB, id driver.TransactionID) {
+	ctx, cancel := context.WithTimeout(context.Background(), transactionAbortTimeout)
+	defer cancel()
+	_ = db.AbortTransaction(ctx, id, nil)
+}
+
+// IsWriteConflict returns true if the error is caused by a write-write conflict between transactions
+func IsWriteConflict(err error) bool {
+	var ae driver.ArangoError
+	return errors.As(err, &ae) && ae.ErrorNum == ErrConflict && ae.Code == 409
+}
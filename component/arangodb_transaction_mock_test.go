@@ -0,0 +1,200 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package component
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"sync"
+)
+
+var (
+	locktransactionDBMockAbortTransaction  sync.RWMutex
+	locktransactionDBMockBeginTransaction  sync.RWMutex
+	locktransactionDBMockCommitTransaction sync.RWMutex
+)
+
+// Ensure, that transactionDBMock does implement transactionDB.
+// If this is not the case, regenerate this file with moq.
+var _ transactionDB = &transactionDBMock{}
+
+// transactionDBMock is a mock implementation of transactionDB.
+//
+//     func TestSomethingThatUsestransactionDB(t *testing.T) {
+//
+//         // make and configure a mocked transactionDB
+//         mockedtransactionDB := &transactionDBMock{
+//             AbortTransactionFunc: func(ctx context.Context, tid driver.TransactionID, opts *driver.AbortTransactionOptions) error {
+// 	               panic("mock out the AbortTransaction method")
+//             },
+//             BeginTransactionFunc: func(ctx context.Context, cols driver.TransactionCollections, opts *driver.BeginTransactionOptions) (driver.TransactionID, error) {
+// 	               panic("mock out the BeginTransaction method")
+//             },
+//             CommitTransactionFunc: func(ctx context.Context, tid driver.TransactionID, opts *driver.CommitTransactionOptions) error {
+// 	               panic("mock out the CommitTransaction method")
+//             },
+//         }
+//
+//         // use mockedtransactionDB in code that requires transactionDB
+//         // and then make assertions.
+//
+//     }
+type transactionDBMock struct {
+	// AbortTransactionFunc mocks the AbortTransaction method.
+	AbortTransactionFunc func(ctx context.Context, tid driver.TransactionID, opts *driver.AbortTransactionOptions) error
+
+	// BeginTransactionFunc mocks the BeginTransaction method.
+	BeginTransactionFunc func(ctx context.Context, cols driver.TransactionCollections, opts *driver.BeginTransactionOptions) (driver.TransactionID, error)
+
+	// CommitTransactionFunc mocks the CommitTransaction method.
+	CommitTransactionFunc func(ctx context.Context, tid driver.TransactionID, opts *driver.CommitTransactionOptions) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AbortTransaction holds details about calls to the AbortTransaction method.
+		AbortTransaction []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Tid is the tid argument value.
+			Tid driver.TransactionID
+			// Opts is the opts argument value.
+			Opts *driver.AbortTransactionOptions
+		}
+		// BeginTransaction holds details about calls to the BeginTransaction method.
+		BeginTransaction []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cols is the cols argument value.
+			Cols driver.TransactionCollections
+			// Opts is the opts argument value.
+			Opts *driver.BeginTransactionOptions
+		}
+		// CommitTransaction holds details about calls to the CommitTransaction method.
+		CommitTransaction []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Tid is the tid argument value.
+			Tid driver.TransactionID
+			// Opts is the opts argument value.
+			Opts *driver.CommitTransactionOptions
+		}
+	}
+}
+
+// AbortTransaction calls AbortTransactionFunc.
+func (mock *transactionDBMock) AbortTransaction(ctx context.Context, tid driver.TransactionID, opts *driver.AbortTransactionOptions) error {
+	if mock.AbortTransactionFunc == nil {
+		panic("transactionDBMock.AbortTransactionFunc: method is nil but transactionDB.AbortTransaction was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Tid  driver.TransactionID
+		Opts *driver.AbortTransactionOptions
+	}{
+		Ctx:  ctx,
+		Tid:  tid,
+		Opts: opts,
+	}
+	locktransactionDBMockAbortTransaction.Lock()
+	mock.calls.AbortTransaction = append(mock.calls.AbortTransaction, callInfo)
+	locktransactionDBMockAbortTransaction.Unlock()
+	return mock.AbortTransactionFunc(ctx, tid, opts)
+}
+
+// AbortTransactionCalls gets all the calls that were made to AbortTransaction.
+// Check the length with:
+//     len(mockedtransactionDB.AbortTransactionCalls())
+func (mock *transactionDBMock) AbortTransactionCalls() []struct {
+	Ctx  context.Context
+	Tid  driver.TransactionID
+	Opts *driver.AbortTransactionOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Tid  driver.TransactionID
+		Opts *driver.AbortTransactionOptions
+	}
+	locktransactionDBMockAbortTransaction.RLock()
+	calls = mock.calls.AbortTransaction
+	locktransactionDBMockAbortTransaction.RUnlock()
+	return calls
+}
+
+// BeginTransaction calls BeginTransactionFunc.
+func (mock *transactionDBMock) BeginTransaction(ctx context.Context, cols driver.TransactionCollections, opts *driver.BeginTransactionOptions) (driver.TransactionID, error) {
+	if mock.BeginTransactionFunc == nil {
+		panic("transactionDBMock.BeginTransactionFunc: method is nil but transactionDB.BeginTransaction was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Cols driver.TransactionCollections
+		Opts *driver.BeginTransactionOptions
+	}{
+		Ctx:  ctx,
+		Cols: cols,
+		Opts: opts,
+	}
+	locktransactionDBMockBeginTransaction.Lock()
+	mock.calls.BeginTransaction = append(mock.calls.BeginTransaction, callInfo)
+	locktransactionDBMockBeginTransaction.Unlock()
+	return mock.BeginTransactionFunc(ctx, cols, opts)
+}
+
+// BeginTransactionCalls gets all the calls that were made to BeginTransaction.
+// Check the length with:
+//     len(mockedtransactionDB.BeginTransactionCalls())
+func (mock *transactionDBMock) BeginTransactionCalls() []struct {
+	Ctx  context.Context
+	Cols driver.TransactionCollections
+	Opts *driver.BeginTransactionOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Cols driver.TransactionCollections
+		Opts *driver.BeginTransactionOptions
+	}
+	locktransactionDBMockBeginTransaction.RLock()
+	calls = mock.calls.BeginTransaction
+	locktransactionDBMockBeginTransaction.RUnlock()
+	return calls
+}
+
+// CommitTransaction calls CommitTransactionFunc.
+func (mock *transactionDBMock) CommitTransaction(ctx context.Context, tid driver.TransactionID, opts *driver.CommitTransactionOptions) error {
+	if mock.CommitTransactionFunc == nil {
+		panic("transactionDBMock.CommitTransactionFunc: method is nil but transactionDB.CommitTransaction was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Tid  driver.TransactionID
+		Opts *driver.CommitTransactionOptions
+	}{
+		Ctx:  ctx,
+		Tid:  tid,
+		Opts: opts,
+	}
+	locktransactionDBMockCommitTransaction.Lock()
+	mock.calls.CommitTransaction = append(mock.calls.CommitTransaction, callInfo)
+	locktransactionDBMockCommitTransaction.Unlock()
+	return mock.CommitTransactionFunc(ctx, tid, opts)
+}
+
+// CommitTransactionCalls gets all the calls that were made to CommitTransaction.
+// Check the length with:
+//     len(mockedtransactionDB.CommitTransactionCalls())
+func (mock *transactionDBMock) CommitTransactionCalls() []struct {
+	Ctx  context.Context
+	Tid  driver.TransactionID
+	Opts *driver.CommitTransactionOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Tid  driver.TransactionID
+		Opts *driver.CommitTransactionOptions
+	}
+	locktransactionDBMockCommitTransaction.RLock()
+	calls = mock.calls.CommitTransaction
+	locktransactionDBMockCommitTransaction.RUnlock()
+	return calls
+}
@@ -0,0 +1,107 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithTransaction(t *testing.T) {
+	conflict := driver.ArangoError{HasError: true, Code: 409, ErrorNum: ErrConflict}
+	testCases := map[string]struct {
+		fnErrs          []error
+		expectedErr     error
+		expectedCalls   int
+		expectedCommits int
+		expectedAborts  int
+	}{
+		"if the function succeeds it should commit": {
+			fnErrs:          []error{nil},
+			expectedCalls:   1,
+			expectedCommits: 1,
+		},
+		"if the function fails it should abort": {
+			fnErrs:         []error{fmt.Errorf("test")},
+			expectedErr:    fmt.Errorf("test"),
+			expectedCalls:  1,
+			expectedAborts: 1,
+		},
+		"if there is a write-write conflict it should retry": {
+			fnErrs:          []error{TranslateError(conflict), nil},
+			expectedCalls:   2,
+			expectedCommits: 1,
+			expectedAborts:  1,
+		},
+		"if the conflicts continue it should stop retrying": {
+			fnErrs:         []error{conflict, conflict, conflict},
+			expectedErr:    conflict,
+			expectedCalls:  3,
+			expectedAborts: 3,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := &transactionDBMock{
+				BeginTransactionFunc: func(context.Context, driver.TransactionCollections, *driver.BeginTransactionOptions) (driver.TransactionID, error) {
+					return "1", nil
+				},
+				CommitTransactionFunc: func(context.Context, driver.TransactionID, *driver.CommitTransactionOptions) error {
+					return nil
+				},
+				AbortTransactionFunc: func(context.Context, driver.TransactionID, *driver.AbortTransactionOptions) error {
+					return nil
+				},
+			}
+			calls := 0
+			err := withTransaction(context.Background(), db, []string{"a"}, []string{"b"}, func(ctx context.Context) error {
+				id, ok := TransactionID(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, driver.TransactionID("1"), id)
+				calls++
+				return tc.fnErrs[calls-1]
+			}, OptionTransactionRetries(2), OptionTransactionBackoff(time.Millisecond, time.Millisecond))
+
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.Len(t, db.CommitTransactionCalls(), tc.expectedCommits)
+			assert.Len(t, db.AbortTransactionCalls(), tc.expectedAborts)
+		})
+	}
+}
+
+func TestWithTransactionPanic(t *testing.T) {
+	db := &transactionDBMock{
+		BeginTransactionFunc: func(context.Context, driver.TransactionCollections, *driver.BeginTransactionOptions) (driver.TransactionID, error) {
+			return "1", nil
+		},
+		AbortTransactionFunc: func(context.Context, driver.TransactionID, *driver.AbortTransactionOptions) error {
+			return nil
+		},
+	}
+
+	assert.Panics(t, func() {
+		_ = withTransaction(context.Background(), db, nil, []string{"b"}, func(ctx context.Context) error {
+			panic("test")
+		})
+	})
+	assert.Len(t, db.AbortTransactionCalls(), 1)
+}
+
+func TestWithTransactionNested(t *testing.T) {
+	db := &transactionDBMock{}
+	ctx := context.WithValue(context.Background(), transactionKey{}, driver.TransactionID("1"))
+
+	err := withTransaction(ctx, db, nil, []string{"b"}, func(ctx context.Context) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, db.BeginTransactionCalls(), 0)
+}
@@ -0,0 +1,774 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package component
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"sync"
+)
+
+var (
+	lockuserMockAccessibleDatabases    sync.RWMutex
+	lockuserMockExtra                  sync.RWMutex
+	lockuserMockGetCollectionAccess    sync.RWMutex
+	lockuserMockGetDatabaseAccess      sync.RWMutex
+	lockuserMockGrantReadWriteAccess   sync.RWMutex
+	lockuserMockIsActive               sync.RWMutex
+	lockuserMockIsPasswordChangeNeeded sync.RWMutex
+	lockuserMockName                   sync.RWMutex
+	lockuserMockRemove                 sync.RWMutex
+	lockuserMockRemoveCollectionAccess sync.RWMutex
+	lockuserMockRemoveDatabaseAccess   sync.RWMutex
+	lockuserMockReplace                sync.RWMutex
+	lockuserMockRevokeAccess           sync.RWMutex
+	lockuserMockSetCollectionAccess    sync.RWMutex
+	lockuserMockSetDatabaseAccess      sync.RWMutex
+	lockuserMockUpdate                 sync.RWMutex
+)
+
+// Ensure, that userMock does implement driver.User.
+// If this is not the case, regenerate this file with moq.
+var _ driver.User = &userMock{}
+
+// userMock is a mock implementation of driver.User.
+//
+//     func TestSomethingThatUsesUser(t *testing.T) {
+//
+//         // make and configure a mocked driver.User
+//         mockedUser := &userMock{
+//             AccessibleDatabasesFunc: func(ctx context.Context) ([]driver.Database, error) {
+// 	               panic("mock out the AccessibleDatabases method")
+//             },
+//             ExtraFunc: func(result interface{}) error {
+// 	               panic("mock out the Extra method")
+//             },
+//             GetCollectionAccessFunc: func(ctx context.Context, col driver.AccessTarget) (driver.Grant, error) {
+// 	               panic("mock out the GetCollectionAccess method")
+//             },
+//             GetDatabaseAccessFunc: func(ctx context.Context, db driver.Database) (driver.Grant, error) {
+// 	               panic("mock out the GetDatabaseAccess method")
+//             },
+//             GrantReadWriteAccessFunc: func(ctx context.Context, db driver.Database) error {
+// 	               panic("mock out the GrantReadWriteAccess method")
+//             },
+//             IsActiveFunc: func() bool {
+// 	               panic("mock out the IsActive method")
+//             },
+//             IsPasswordChangeNeededFunc: func() bool {
+// 	               panic("mock out the IsPasswordChangeNeeded method")
+//             },
+//             NameFunc: func() string {
+// 	               panic("mock out the Name method")
+//             },
+//             RemoveFunc: func(ctx context.Context) error {
+// 	               panic("mock out the Remove method")
+//             },
+//             RemoveCollectionAccessFunc: func(ctx context.Context, col driver.AccessTarget) error {
+// 	               panic("mock out the RemoveCollectionAccess method")
+//             },
+//             RemoveDatabaseAccessFunc: func(ctx context.Context, db driver.Database) error {
+// 	               panic("mock out the RemoveDatabaseAccess method")
+//             },
+//             ReplaceFunc: func(ctx context.Context, options driver.UserOptions) error {
+// 	               panic("mock out the Replace method")
+//             },
+//             RevokeAccessFunc: func(ctx context.Context, db driver.Database) error {
+// 	               panic("mock out the RevokeAccess method")
+//             },
+//             SetCollectionAccessFunc: func(ctx context.Context, col driver.AccessTarget, access driver.Grant) error {
+// 	               panic("mock out the SetCollectionAccess method")
+//             },
+//             SetDatabaseAccessFunc: func(ctx context.Context, db driver.Database, access driver.Grant) error {
+// 	               panic("mock out the SetDatabaseAccess method")
+//             },
+//             UpdateFunc: func(ctx context.Context, options driver.UserOptions) error {
+// 	               panic("mock out the Update method")
+//             },
+//         }
+//
+//         // use mockedUser in code that requires driver.User
+//         // and then make assertions.
+//
+//     }
+type userMock struct {
+	// AccessibleDatabasesFunc mocks the AccessibleDatabases method.
+	AccessibleDatabasesFunc func(ctx context.Context) ([]driver.Database, error)
+
+	// ExtraFunc mocks the Extra method.
+	ExtraFunc func(result interface{}) error
+
+	// GetCollectionAccessFunc mocks the GetCollectionAccess method.
+	GetCollectionAccessFunc func(ctx context.Context, col driver.AccessTarget) (driver.Grant, error)
+
+	// GetDatabaseAccessFunc mocks the GetDatabaseAccess method.
+	GetDatabaseAccessFunc func(ctx context.Context, db driver.Database) (driver.Grant, error)
+
+	// GrantReadWriteAccessFunc mocks the GrantReadWriteAccess method.
+	GrantReadWriteAccessFunc func(ctx context.Context, db driver.Database) error
+
+	// IsActiveFunc mocks the IsActive method.
+	IsActiveFunc func() bool
+
+	// IsPasswordChangeNeededFunc mocks the IsPasswordChangeNeeded method.
+	IsPasswordChangeNeededFunc func() bool
+
+	// NameFunc mocks the Name method.
+	NameFunc func() string
+
+	// RemoveFunc mocks the Remove method.
+	RemoveFunc func(ctx context.Context) error
+
+	// RemoveCollectionAccessFunc mocks the RemoveCollectionAccess method.
+	RemoveCollectionAccessFunc func(ctx context.Context, col driver.AccessTarget) error
+
+	// RemoveDatabaseAccessFunc mocks the RemoveDatabaseAccess method.
+	RemoveDatabaseAccessFunc func(ctx context.Context, db driver.Database) error
+
+	// ReplaceFunc mocks the Replace method.
+	ReplaceFunc func(ctx context.Context, options driver.UserOptions) error
+
+	// RevokeAccessFunc mocks the RevokeAccess method.
+	RevokeAccessFunc func(ctx context.Context, db driver.Database) error
+
+	// SetCollectionAccessFunc mocks the SetCollectionAccess method.
+	SetCollectionAccessFunc func(ctx context.Context, col driver.AccessTarget, access driver.Grant) error
+
+	// SetDatabaseAccessFunc mocks the SetDatabaseAccess method.
+	SetDatabaseAccessFunc func(ctx context.Context, db driver.Database, access driver.Grant) error
+
+	// UpdateFunc mocks the Update method.
+	UpdateFunc func(ctx context.Context, options driver.UserOptions) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AccessibleDatabases holds details about calls to the AccessibleDatabases method.
+		AccessibleDatabases []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Extra holds details about calls to the Extra method.
+		Extra []struct {
+			// Result is the result argument value.
+			Result interface{}
+		}
+		// GetCollectionAccess holds details about calls to the GetCollectionAccess method.
+		GetCollectionAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Col is the col argument value.
+			Col driver.AccessTarget
+		}
+		// GetDatabaseAccess holds details about calls to the GetDatabaseAccess method.
+		GetDatabaseAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db driver.Database
+		}
+		// GrantReadWriteAccess holds details about calls to the GrantReadWriteAccess method.
+		GrantReadWriteAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db driver.Database
+		}
+		// IsActive holds details about calls to the IsActive method.
+		IsActive []struct {
+		}
+		// IsPasswordChangeNeeded holds details about calls to the IsPasswordChangeNeeded method.
+		IsPasswordChangeNeeded []struct {
+		}
+		// Name holds details about calls to the Name method.
+		Name []struct {
+		}
+		// Remove holds details about calls to the Remove method.
+		Remove []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// RemoveCollectionAccess holds details about calls to the RemoveCollectionAccess method.
+		RemoveCollectionAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Col is the col argument value.
+			Col driver.AccessTarget
+		}
+		// RemoveDatabaseAccess holds details about calls to the RemoveDatabaseAccess method.
+		RemoveDatabaseAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db driver.Database
+		}
+		// Replace holds details about calls to the Replace method.
+		Replace []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options driver.UserOptions
+		}
+		// RevokeAccess holds details about calls to the RevokeAccess method.
+		RevokeAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db driver.Database
+		}
+		// SetCollectionAccess holds details about calls to the SetCollectionAccess method.
+		SetCollectionAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Col is the col argument value.
+			Col driver.AccessTarget
+			// Access is the access argument value.
+			Access driver.Grant
+		}
+		// SetDatabaseAccess holds details about calls to the SetDatabaseAccess method.
+		SetDatabaseAccess []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Db is the db argument value.
+			Db driver.Database
+			// Access is the access argument value.
+			Access driver.Grant
+		}
+		// Update holds details about calls to the Update method.
+		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options driver.UserOptions
+		}
+	}
+}
+
+// AccessibleDatabases calls AccessibleDatabasesFunc.
+func (mock *userMock) AccessibleDatabases(ctx context.Context) ([]driver.Database, error) {
+	if mock.AccessibleDatabasesFunc == nil {
+		panic("userMock.AccessibleDatabasesFunc: method is nil but User.AccessibleDatabases was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockuserMockAccessibleDatabases.Lock()
+	mock.calls.AccessibleDatabases = append(mock.calls.AccessibleDatabases, callInfo)
+	lockuserMockAccessibleDatabases.Unlock()
+	return mock.AccessibleDatabasesFunc(ctx)
+}
+
+// AccessibleDatabasesCalls gets all the calls that were made to AccessibleDatabases.
+// Check the length with:
+//     len(mockedUser.AccessibleDatabasesCalls())
+func (mock *userMock) AccessibleDatabasesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockuserMockAccessibleDatabases.RLock()
+	calls = mock.calls.AccessibleDatabases
+	lockuserMockAccessibleDatabases.RUnlock()
+	return calls
+}
+
+// Extra calls ExtraFunc.
+func (mock *userMock) Extra(result interface{}) error {
+	if mock.ExtraFunc == nil {
+		panic("userMock.ExtraFunc: method is nil but User.Extra was just called")
+	}
+	callInfo := struct {
+		Result interface{}
+	}{
+		Result: result,
+	}
+	lockuserMockExtra.Lock()
+	mock.calls.Extra = append(mock.calls.Extra, callInfo)
+	lockuserMockExtra.Unlock()
+	return mock.ExtraFunc(result)
+}
+
+// ExtraCalls gets all the calls that were made to Extra.
+// Check the length with:
+//     len(mockedUser.ExtraCalls())
+func (mock *userMock) ExtraCalls() []struct {
+	Result interface{}
+} {
+	var calls []struct {
+		Result interface{}
+	}
+	lockuserMockExtra.RLock()
+	calls = mock.calls.Extra
+	lockuserMockExtra.RUnlock()
+	return calls
+}
+
+// GetCollectionAccess calls GetCollectionAccessFunc.
+func (mock *userMock) GetCollectionAccess(ctx context.Context, col driver.AccessTarget) (driver.Grant, error) {
+	if mock.GetCollectionAccessFunc == nil {
+		panic("userMock.GetCollectionAccessFunc: method is nil but User.GetCollectionAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Col driver.AccessTarget
+	}{
+		Ctx: ctx,
+		Col: col,
+	}
+	lockuserMockGetCollectionAccess.Lock()
+	mock.calls.GetCollectionAccess = append(mock.calls.GetCollectionAccess, callInfo)
+	lockuserMockGetCollectionAccess.Unlock()
+	return mock.GetCollectionAccessFunc(ctx, col)
+}
+
+// GetCollectionAccessCalls gets all the calls that were made to GetCollectionAccess.
+// Check the length with:
+//     len(mockedUser.GetCollectionAccessCalls())
+func (mock *userMock) GetCollectionAccessCalls() []struct {
+	Ctx context.Context
+	Col driver.AccessTarget
+} {
+	var calls []struct {
+		Ctx context.Context
+		Col driver.AccessTarget
+	}
+	lockuserMockGetCollectionAccess.RLock()
+	calls = mock.calls.GetCollectionAccess
+	lockuserMockGetCollectionAccess.RUnlock()
+	return calls
+}
+
+// GetDatabaseAccess calls GetDatabaseAccessFunc.
+func (mock *userMock) GetDatabaseAccess(ctx context.Context, db driver.Database) (driver.Grant, error) {
+	if mock.GetDatabaseAccessFunc == nil {
+		panic("userMock.GetDatabaseAccessFunc: method is nil but User.GetDatabaseAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  driver.Database
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	lockuserMockGetDatabaseAccess.Lock()
+	mock.calls.GetDatabaseAccess = append(mock.calls.GetDatabaseAccess, callInfo)
+	lockuserMockGetDatabaseAccess.Unlock()
+	return mock.GetDatabaseAccessFunc(ctx, db)
+}
+
+// GetDatabaseAccessCalls gets all the calls that were made to GetDatabaseAccess.
+// Check the length with:
+//     len(mockedUser.GetDatabaseAccessCalls())
+func (mock *userMock) GetDatabaseAccessCalls() []struct {
+	Ctx context.Context
+	Db  driver.Database
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  driver.Database
+	}
+	lockuserMockGetDatabaseAccess.RLock()
+	calls = mock.calls.GetDatabaseAccess
+	lockuserMockGetDatabaseAccess.RUnlock()
+	return calls
+}
+
+// GrantReadWriteAccess calls GrantReadWriteAccessFunc.
+func (mock *userMock) GrantReadWriteAccess(ctx context.Context, db driver.Database) error {
+	if mock.GrantReadWriteAccessFunc == nil {
+		panic("userMock.GrantReadWriteAccessFunc: method is nil but User.GrantReadWriteAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  driver.Database
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	lockuserMockGrantReadWriteAccess.Lock()
+	mock.calls.GrantReadWriteAccess = append(mock.calls.GrantReadWriteAccess, callInfo)
+	lockuserMockGrantReadWriteAccess.Unlock()
+	return mock.GrantReadWriteAccessFunc(ctx, db)
+}
+
+// GrantReadWriteAccessCalls gets all the calls that were made to GrantReadWriteAccess.
+// Check the length with:
+//     len(mockedUser.GrantReadWriteAccessCalls())
+func (mock *userMock) GrantReadWriteAccessCalls() []struct {
+	Ctx context.Context
+	Db  driver.Database
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  driver.Database
+	}
+	lockuserMockGrantReadWriteAccess.RLock()
+	calls = mock.calls.GrantReadWriteAccess
+	lockuserMockGrantReadWriteAccess.RUnlock()
+	return calls
+}
+
+// IsActive calls IsActiveFunc.
+func (mock *userMock) IsActive() bool {
+	if mock.IsActiveFunc == nil {
+		panic("userMock.IsActiveFunc: method is nil but User.IsActive was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockuserMockIsActive.Lock()
+	mock.calls.IsActive = append(mock.calls.IsActive, callInfo)
+	lockuserMockIsActive.Unlock()
+	return mock.IsActiveFunc()
+}
+
+// IsActiveCalls gets all the calls that were made to IsActive.
+// Check the length with:
+//     len(mockedUser.IsActiveCalls())
+func (mock *userMock) IsActiveCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockuserMockIsActive.RLock()
+	calls = mock.calls.IsActive
+	lockuserMockIsActive.RUnlock()
+	return calls
+}
+
+// IsPasswordChangeNeeded calls IsPasswordChangeNeededFunc.
+func (mock *userMock) IsPasswordChangeNeeded() bool {
+	if mock.IsPasswordChangeNeededFunc == nil {
+		panic("userMock.IsPasswordChangeNeededFunc: method is nil but User.IsPasswordChangeNeeded was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockuserMockIsPasswordChangeNeeded.Lock()
+	mock.calls.IsPasswordChangeNeeded = append(mock.calls.IsPasswordChangeNeeded, callInfo)
+	lockuserMockIsPasswordChangeNeeded.Unlock()
+	return mock.IsPasswordChangeNeededFunc()
+}
+
+// IsPasswordChangeNeededCalls gets all the calls that were made to IsPasswordChangeNeeded.
+// Check the length with:
+//     len(mockedUser.IsPasswordChangeNeededCalls())
+func (mock *userMock) IsPasswordChangeNeededCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockuserMockIsPasswordChangeNeeded.RLock()
+	calls = mock.calls.IsPasswordChangeNeeded
+	lockuserMockIsPasswordChangeNeeded.RUnlock()
+	return calls
+}
+
+// Name calls NameFunc.
+func (mock *userMock) Name() string {
+	if mock.NameFunc == nil {
+		panic("userMock.NameFunc: method is nil but User.Name was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockuserMockName.Lock()
+	mock.calls.Name = append(mock.calls.Name, callInfo)
+	lockuserMockName.Unlock()
+	return mock.NameFunc()
+}
+
+// NameCalls gets all the calls that were made to Name.
+// Check the length with:
+//     len(mockedUser.NameCalls())
+func (mock *userMock) NameCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockuserMockName.RLock()
+	calls = mock.calls.Name
+	lockuserMockName.RUnlock()
+	return calls
+}
+
+// Remove calls RemoveFunc.
+func (mock *userMock) Remove(ctx context.Context) error {
+	if mock.RemoveFunc == nil {
+		panic("userMock.RemoveFunc: method is nil but User.Remove was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockuserMockRemove.Lock()
+	mock.calls.Remove = append(mock.calls.Remove, callInfo)
+	lockuserMockRemove.Unlock()
+	return mock.RemoveFunc(ctx)
+}
+
+// RemoveCalls gets all the calls that were made to Remove.
+// Check the length with:
+//     len(mockedUser.RemoveCalls())
+func (mock *userMock) RemoveCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockuserMockRemove.RLock()
+	calls = mock.calls.Remove
+	lockuserMockRemove.RUnlock()
+	return calls
+}
+
+// RemoveCollectionAccess calls RemoveCollectionAccessFunc.
+func (mock *userMock) RemoveCollectionAccess(ctx context.Context, col driver.AccessTarget) error {
+	if mock.RemoveCollectionAccessFunc == nil {
+		panic("userMock.RemoveCollectionAccessFunc: method is nil but User.RemoveCollectionAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Col driver.AccessTarget
+	}{
+		Ctx: ctx,
+		Col: col,
+	}
+	lockuserMockRemoveCollectionAccess.Lock()
+	mock.calls.RemoveCollectionAccess = append(mock.calls.RemoveCollectionAccess, callInfo)
+	lockuserMockRemoveCollectionAccess.Unlock()
+	return mock.RemoveCollectionAccessFunc(ctx, col)
+}
+
+// RemoveCollectionAccessCalls gets all the calls that were made to RemoveCollectionAccess.
+// Check the length with:
+//     len(mockedUser.RemoveCollectionAccessCalls())
+func (mock *userMock) RemoveCollectionAccessCalls() []struct {
+	Ctx context.Context
+	Col driver.AccessTarget
+} {
+	var calls []struct {
+		Ctx context.Context
+		Col driver.AccessTarget
+	}
+	lockuserMockRemoveCollectionAccess.RLock()
+	calls = mock.calls.RemoveCollectionAccess
+	lockuserMockRemoveCollectionAccess.RUnlock()
+	return calls
+}
+
+// RemoveDatabaseAccess calls RemoveDatabaseAccessFunc.
+func (mock *userMock) RemoveDatabaseAccess(ctx context.Context, db driver.Database) error {
+	if mock.RemoveDatabaseAccessFunc == nil {
+		panic("userMock.RemoveDatabaseAccessFunc: method is nil but User.RemoveDatabaseAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  driver.Database
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	lockuserMockRemoveDatabaseAccess.Lock()
+	mock.calls.RemoveDatabaseAccess = append(mock.calls.RemoveDatabaseAccess, callInfo)
+	lockuserMockRemoveDatabaseAccess.Unlock()
+	return mock.RemoveDatabaseAccessFunc(ctx, db)
+}
+
+// RemoveDatabaseAccessCalls gets all the calls that were made to RemoveDatabaseAccess.
+// Check the length with:
+//     len(mockedUser.RemoveDatabaseAccessCalls())
+func (mock *userMock) RemoveDatabaseAccessCalls() []struct {
+	Ctx context.Context
+	Db  driver.Database
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  driver.Database
+	}
+	lockuserMockRemoveDatabaseAccess.RLock()
+	calls = mock.calls.RemoveDatabaseAccess
+	lockuserMockRemoveDatabaseAccess.RUnlock()
+	return calls
+}
+
+// Replace calls ReplaceFunc.
+func (mock *userMock) Replace(ctx context.Context, options driver.UserOptions) error {
+	if mock.ReplaceFunc == nil {
+		panic("userMock.ReplaceFunc: method is nil but User.Replace was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Options driver.UserOptions
+	}{
+		Ctx:     ctx,
+		Options: options,
+	}
+	lockuserMockReplace.Lock()
+	mock.calls.Replace = append(mock.calls.Replace, callInfo)
+	lockuserMockReplace.Unlock()
+	return mock.ReplaceFunc(ctx, options)
+}
+
+// ReplaceCalls gets all the calls that were made to Replace.
+// Check the length with:
+//     len(mockedUser.ReplaceCalls())
+func (mock *userMock) ReplaceCalls() []struct {
+	Ctx     context.Context
+	Options driver.UserOptions
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Options driver.UserOptions
+	}
+	lockuserMockReplace.RLock()
+	calls = mock.calls.Replace
+	lockuserMockReplace.RUnlock()
+	return calls
+}
+
+// RevokeAccess calls RevokeAccessFunc.
+func (mock *userMock) RevokeAccess(ctx context.Context, db driver.Database) error {
+	if mock.RevokeAccessFunc == nil {
+		panic("userMock.RevokeAccessFunc: method is nil but User.RevokeAccess was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Db  driver.Database
+	}{
+		Ctx: ctx,
+		Db:  db,
+	}
+	lockuserMockRevokeAccess.Lock()
+	mock.calls.RevokeAccess = append(mock.calls.RevokeAccess, callInfo)
+	lockuserMockRevokeAccess.Unlock()
+	return mock.RevokeAccessFunc(ctx, db)
+}
+
+// RevokeAccessCalls gets all the calls that were made to RevokeAccess.
+// Check the length with:
+//     len(mockedUser.RevokeAccessCalls())
+func (mock *userMock) RevokeAccessCalls() []struct {
+	Ctx context.Context
+	Db  driver.Database
+} {
+	var calls []struct {
+		Ctx context.Context
+		Db  driver.Database
+	}
+	lockuserMockRevokeAccess.RLock()
+	calls = mock.calls.RevokeAccess
+	lockuserMockRevokeAccess.RUnlock()
+	return calls
+}
+
+// SetCollectionAccess calls SetCollectionAccessFunc.
+func (mock *userMock) SetCollectionAccess(ctx context.Context, col driver.AccessTarget, access driver.Grant) error {
+	if mock.SetCollectionAccessFunc == nil {
+		panic("userMock.SetCollectionAccessFunc: method is nil but User.SetCollectionAccess was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Col    driver.AccessTarget
+		Access driver.Grant
+	}{
+		Ctx:    ctx,
+		Col:    col,
+		Access: access,
+	}
+	lockuserMockSetCollectionAccess.Lock()
+	mock.calls.SetCollectionAccess = append(mock.calls.SetCollectionAccess, callInfo)
+	lockuserMockSetCollectionAccess.Unlock()
+	return mock.SetCollectionAccessFunc(ctx, col, access)
+}
+
+// SetCollectionAccessCalls gets all the calls that were made to SetCollectionAccess.
+// Check the length with:
+//     len(mockedUser.SetCollectionAccessCalls())
+func (mock *userMock) SetCollectionAccessCalls() []struct {
+	Ctx    context.Context
+	Col    driver.AccessTarget
+	Access driver.Grant
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Col    driver.AccessTarget
+		Access driver.Grant
+	}
+	lockuserMockSetCollectionAccess.RLock()
+	calls = mock.calls.SetCollectionAccess
+	lockuserMockSetCollectionAccess.RUnlock()
+	return calls
+}
+
+// SetDatabaseAccess calls SetDatabaseAccessFunc.
+func (mock *userMock) SetDatabaseAccess(ctx context.Context, db driver.Database, access driver.Grant) error {
+	if mock.SetDatabaseAccessFunc == nil {
+		panic("userMock.SetDatabaseAccessFunc: method is nil but User.SetDatabaseAccess was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Db     driver.Database
+		Access driver.Grant
+	}{
+		Ctx:    ctx,
+		Db:     db,
+		Access: access,
+	}
+	lockuserMockSetDatabaseAccess.Lock()
+	mock.calls.SetDatabaseAccess = append(mock.calls.SetDatabaseAccess, callInfo)
+	lockuserMockSetDatabaseAccess.Unlock()
+	return mock.SetDatabaseAccessFunc(ctx, db, access)
+}
+
+// SetDatabaseAccessCalls gets all the calls that were made to SetDatabaseAccess.
+// Check the length with:
+//     len(mockedUser.SetDatabaseAccessCalls())
+func (mock *userMock) SetDatabaseAccessCalls() []struct {
+	Ctx    context.Context
+	Db     driver.Database
+	Access driver.Grant
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Db     driver.Database
+		Access driver.Grant
+	}
+	lockuserMockSetDatabaseAccess.RLock()
+	calls = mock.calls.SetDatabaseAccess
+	lockuserMockSetDatabaseAccess.RUnlock()
+	return calls
+}
+
+// Update calls UpdateFunc.
+func (mock *userMock) Update(ctx context.Context, options driver.UserOptions) error {
+	if mock.UpdateFunc == nil {
+		panic("userMock.UpdateFunc: method is nil but User.Update was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Options driver.UserOptions
+	}{
+		Ctx:     ctx,
+		Options: options,
+	}
+	lockuserMockUpdate.Lock()
+	mock.calls.Update = append(mock.calls.Update, callInfo)
+	lockuserMockUpdate.Unlock()
+	return mock.UpdateFunc(ctx, options)
+}
+
+// UpdateCalls gets all the calls that were made to Update.
+// Check the length with:
+//     len(mockedUser.UpdateCalls())
+func (mock *userMock) UpdateCalls() []struct {
+	Ctx     context.Context
+	Options driver.UserOptions
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Options driver.UserOptions
+	}
+	lockuserMockUpdate.RLock()
+	calls = mock.calls.Update
+	lockuserMockUpdate.RUnlock()
+	return calls
+}
@@ -0,0 +1,52 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+)
+
+// View is an ArangoSearch view, the analyzers are created before the view so they can be used in its links
+type View struct {
+	Name       string
+	Analyzers  []driver.ArangoSearchAnalyzerDefinition
+	Properties driver.ArangoSearchViewProperties
+}
+
+// CreateViews creates the analyzers and the views, if a view already exists its properties are updated
+func (a *Arango) CreateViews(ctx context.Context, views []View) error {
+	for _, v := range views {
+		for _, an := range v.Analyzers {
+			if _, _, err := a.clientDB.EnsureAnalyzer(ctx, an); err != nil {
+				return fmt.Errorf("unable to create analyzer '%s': %w", an.Name, err)
+			}
+		}
+
+		props := v.Properties
+		exists, err := a.clientDB.ViewExists(ctx, v.Name)
+		if err != nil {
+			return fmt.Errorf("unable to check view '%s': %w", v.Name, err)
+		}
+
+		if !exists {
+			if _, err := a.clientDB.CreateArangoSearchView(ctx, v.Name, &props); err != nil {
+				return fmt.Errorf("unable to create view '%s': %w", v.Name, err)
+			}
+			continue
+		}
+
+		view, err := a.clientDB.View(ctx, v.Name)
+		if err != nil {
+			return fmt.Errorf("unable to get view '%s': %w", v.Name, err)
+		}
+		searchView, err := view.ArangoSearchView()
+		if err != nil {
+			return fmt.Errorf("view '%s' is not an arangosearch view: %w", v.Name, err)
+		}
+		if err := searchView.SetProperties(ctx, props); err != nil {
+			return fmt.Errorf("unable to update view '%s': %w", v.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package component
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCreateViews(t *testing.T) {
+	view := View{
+		Name: "search",
+		Analyzers: []driver.ArangoSearchAnalyzerDefinition{
+			{Name: "text_es", Type: driver.ArangoSearchAnalyzerTypeText},
+		},
+		Properties: driver.ArangoSearchViewProperties{
+			Links: driver.ArangoSearchLinks{"posts": driver.ArangoSearchElementProperties{}},
+		},
+	}
+
+	testCases := map[string]struct {
+		existing []string
+		calls    []string
+	}{
+		"if the view doesn't exist it should be created after the analyzers": {
+			calls: []string{"POST _api/analyzer", "GET _api/view/search", "POST _api/view"},
+		},
+		"if the view exists its properties should be updated": {
+			existing: []string{"search"},
+			calls:    []string{"POST _api/analyzer", "GET _api/view/search", "GET _api/view/search", "PUT _api/view/search/properties"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a, srv := newTestArango(t, tc.existing...)
+
+			err := a.CreateViews(context.Background(), []View{view})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.calls, srv.calls())
+			assert.Equal(t, "text_es", srv.requests[0].Body["name"])
+			assert.Contains(t, srv.requests[len(srv.requests)-1].Body["links"], "posts")
+		})
+	}
+}
@@ -0,0 +1,544 @@
+// Package arangotest provides an in-memory implementation of component.DocumentStore to test the code that uses
+// ArangoDB without a database. It keeps the documents as JSON, enforces the unique indexes, hides the documents
+// expired by the TTL indexes and traverses the edges breadth-first
+package arangotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kayx-org/freja/component"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type document map[string]interface{}
+
+type collection struct {
+	edge    bool
+	docs    map[string]document
+	indexes []component.Index
+}
+
+// Store is an in-memory component.DocumentStore, Now is the clock used to expire the documents of the TTL indexes
+type Store struct {
+	Now func() time.Time
+
+	mutex       sync.Mutex
+	collections map[string]*collection
+	seq         int64
+}
+
+var _ component.DocumentStore = &Store{}
+
+// NewStore creates an empty store, the collections must be created before using them like in a database
+func NewStore() *Store {
+	return &Store{
+		Now:         time.Now,
+		collections: make(map[string]*collection),
+	}
+}
+
+func (s *Store) CreateCollections(_ context.Context, collections []component.Collection) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, c := range collections {
+		s.ensureCollection(c.Name, false, c.Indexes)
+	}
+
+	return nil
+}
+
+func (s *Store) CreateGraph(_ context.Context, graph *component.Graph) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, v := range graph.Vertexes {
+		s.ensureCollection(v.Name, false, v.Indexes)
+	}
+	for _, e := range graph.Edges {
+		s.ensureCollection(e.Name, true, e.Indexes)
+		for _, name := range append(append([]string{}, e.From...), e.To...) {
+			s.ensureCollection(name, false, nil)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) CreateDocument(_ context.Context, collection string, doc interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.create(collection, doc)
+}
+
+func (s *Store) CreateDocuments(_ context.Context, collection string, docs interface{}) error {
+	v := reflect.ValueOf(docs)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("documents of '%s' must be a slice, got %T", collection, docs)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var firstErr error
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+		if err := s.create(collection, item.Interface()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (s *Store) ReadDocument(_ context.Context, collection, key string, result interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, stored, err := s.find(collection, key, "")
+	if err != nil {
+		return err
+	}
+
+	return decode(stored, result)
+}
+
+func (s *Store) UpdateDocument(_ context.Context, collection string, doc interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.write(collection, doc, true, merge)
+}
+
+func (s *Store) ReplaceDocument(_ context.Context, collection string, doc interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.write(collection, doc, true, func(stored, patch document) document {
+		return patch
+	})
+}
+
+func (s *Store) UpsertDocument(_ context.Context, collection string, doc interface{}) error {
+	key, _, err := component.DocumentMeta(doc)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if key != "" {
+		if _, _, err := s.find(collection, key, ""); err == nil {
+			return s.write(collection, doc, false, merge)
+		} else if !errors.Is(err, component.ErrNotFound) {
+			return err
+		}
+	}
+
+	return s.create(collection, doc)
+}
+
+func (s *Store) RemoveDocument(_ context.Context, collection, key, rev string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, _, err := s.find(collection, key, rev)
+	if err != nil {
+		return err
+	}
+	delete(c.docs, key)
+
+	return nil
+}
+
+func (s *Store) Traverse(_ context.Context, start string, edges []string, direction component.EdgeDirection, min, max int, result interface{}) error {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("result must be a pointer to a slice, got %T", result)
+	}
+
+	s.mutex.Lock()
+	vertices, err := s.traverse(start, edges, direction, min, max)
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	items := v.Elem()
+	for _, vertex := range vertices {
+		item := reflect.New(items.Type().Elem())
+		if err := decode(vertex, item.Interface()); err != nil {
+			return err
+		}
+		items = reflect.Append(items, item.Elem())
+	}
+	v.Elem().Set(items)
+
+	return nil
+}
+
+// traverse visits the vertices breadth-first, each one once, returning the ones between min and max depth
+func (s *Store) traverse(start string, edges []string, direction component.EdgeDirection, min, max int) ([]document, error) {
+	first, err := s.vertex(start)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []document
+	if min == 0 {
+		res = append(res, first)
+	}
+	visited := map[string]bool{start: true}
+	level := []string{start}
+	for depth := 1; depth <= max && len(level) > 0; depth++ {
+		var next []string
+		for _, id := range level {
+			for _, neighbour := range s.neighbours(id, edges, direction) {
+				if visited[neighbour] {
+					continue
+				}
+				visited[neighbour] = true
+				vertex, err := s.vertex(neighbour)
+				if err != nil {
+					continue
+				}
+				next = append(next, neighbour)
+				if depth >= min {
+					res = append(res, vertex)
+				}
+			}
+		}
+		level = next
+	}
+
+	return res, nil
+}
+
+func (s *Store) neighbours(id string, edges []string, direction component.EdgeDirection) []string {
+	var res []string
+	for _, name := range edges {
+		c, ok := s.collections[name]
+		if !ok {
+			continue
+		}
+		for _, key := range sortedKeys(c.docs) {
+			edge := c.docs[key]
+			if s.expired(c, edge) {
+				continue
+			}
+			from, _ := edge["_from"].(string)
+			to, _ := edge["_to"].(string)
+			if from == id && direction != component.EdgeInbound {
+				res = append(res, to)
+			}
+			if to == id && direction != component.EdgeOutbound {
+				res = append(res, from)
+			}
+		}
+	}
+
+	return res
+}
+
+func (s *Store) vertex(id string) (document, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid document id '%s'", id)
+	}
+	_, doc, err := s.find(parts[0], parts[1], "")
+
+	return doc, err
+}
+
+func (s *Store) ensureCollection(name string, edge bool, indexes []component.Index) {
+	c, ok := s.collections[name]
+	if !ok {
+		c = &collection{docs: make(map[string]document)}
+		s.collections[name] = c
+	}
+	c.edge = c.edge || edge
+	if indexes != nil {
+		c.indexes = indexes
+	}
+}
+
+func (s *Store) getCollection(name string) (*collection, error) {
+	c, ok := s.collections[name]
+	if !ok {
+		return nil, fmt.Errorf("collection '%s' not found", name)
+	}
+
+	return c, nil
+}
+
+// find returns the document with the key, if rev isn't empty it must be the revision of the document
+func (s *Store) find(collection, key, rev string) (*collection, document, error) {
+	c, err := s.getCollection(collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, ok := c.docs[key]
+	if !ok || s.expired(c, doc) {
+		delete(c.docs, key)
+		return nil, nil, fmt.Errorf("document '%s' in '%s': %w", key, collection, component.ErrNotFound)
+	}
+	if rev != "" && doc["_rev"] != rev {
+		return nil, nil, fmt.Errorf("document '%s' in '%s': %w", key, collection, component.ErrRevisionConflict)
+	}
+
+	return c, doc, nil
+}
+
+func (s *Store) create(collection string, doc interface{}) error {
+	c, err := s.getCollection(collection)
+	if err != nil {
+		return err
+	}
+	stored, err := encode(doc)
+	if err != nil {
+		return err
+	}
+
+	key, _ := stored["_key"].(string)
+	if key == "" {
+		s.seq++
+		key = strconv.FormatInt(s.seq, 10)
+	}
+	if existing, ok := c.docs[key]; ok && !s.expired(c, existing) {
+		return fmt.Errorf("document '%s' in '%s': %w", key, collection, component.ErrAlreadyExists)
+	}
+
+	if err := s.store(collection, c, key, stored); err != nil {
+		return err
+	}
+
+	return decode(stored, doc)
+}
+
+// write replaces the stored document with the result of apply, checking its revision if it's set and checkRev
+func (s *Store) write(collection string, doc interface{}, checkRev bool, apply func(stored, patch document) document) error {
+	key, rev, err := component.DocumentMeta(doc)
+	if err != nil {
+		return err
+	}
+	if !checkRev {
+		rev = ""
+	}
+
+	c, stored, err := s.find(collection, key, rev)
+	if err != nil {
+		return err
+	}
+	patch, err := encode(doc)
+	if err != nil {
+		return err
+	}
+	delete(patch, "_rev")
+
+	updated := apply(copyDocument(stored), patch)
+	if err := s.store(collection, c, key, updated); err != nil {
+		return err
+	}
+
+	return decode(updated, doc)
+}
+
+// store sets the metadata of the document and saves it if it doesn't violate a unique index
+func (s *Store) store(collection string, c *collection, key string, doc document) error {
+	if c.edge {
+		if _, ok := doc["_from"].(string); !ok {
+			return fmt.Errorf("edge '%s' in '%s' without _from", key, collection)
+		}
+		if _, ok := doc["_to"].(string); !ok {
+			return fmt.Errorf("edge '%s' in '%s' without _to", key, collection)
+		}
+	}
+
+	for _, ix := range c.indexes {
+		if !isUnique(ix) {
+			continue
+		}
+		value, ok := indexValue(ix, doc)
+		if !ok {
+			continue
+		}
+		for otherKey, other := range c.docs {
+			if otherKey == key || s.expired(c, other) {
+				continue
+			}
+			if otherValue, ok := indexValue(ix, other); ok && otherValue == value {
+				return fmt.Errorf("unique index '%s' in '%s': %w", ix.Name(), collection, component.ErrAlreadyExists)
+			}
+		}
+	}
+
+	s.seq++
+	doc["_key"] = key
+	doc["_id"] = collection + "/" + key
+	doc["_rev"] = "_" + strconv.FormatInt(s.seq, 36)
+	c.docs[key] = doc
+
+	return nil
+}
+
+// expired returns true if the document is older than the expiration of one of the TTL indexes of the collection
+func (s *Store) expired(c *collection, doc document) bool {
+	for _, ix := range c.indexes {
+		ttl, ok := ix.(component.TTLIndex)
+		if !ok {
+			continue
+		}
+		at, ok := timeValue(field(doc, ttl.IxField))
+		if ok && !s.Now().Before(at.Add(time.Duration(ttl.ExpireAfter)*time.Second)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUnique(ix component.Index) bool {
+	switch index := ix.(type) {
+	case component.HashIndex:
+		return index.Unique
+	case component.PersistentIndex:
+		return index.Unique
+	case component.SkipListIndex:
+		return index.Unique
+	case component.ZKDIndex:
+		return index.Unique
+	default:
+		return false
+	}
+}
+
+func isSparse(ix component.Index) bool {
+	switch index := ix.(type) {
+	case component.HashIndex:
+		return index.Sparse
+	case component.PersistentIndex:
+		return index.Sparse
+	case component.SkipListIndex:
+		return index.Sparse
+	default:
+		return false
+	}
+}
+
+// indexValue returns the values of the fields of the index in the document, the sparse indexes
+// ignore the documents without some of the fields
+func indexValue(ix component.Index, doc document) (string, bool) {
+	values := make([]interface{}, len(ix.Fields()))
+	for i, f := range ix.Fields() {
+		values[i] = field(doc, f)
+		if values[i] == nil && isSparse(ix) {
+			return "", false
+		}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+
+	return string(data), true
+}
+
+// field returns the value of a field of the document, the fields of nested objects are separated by dots
+func field(doc document, path string) interface{} {
+	var value interface{} = map[string]interface{}(doc)
+	for _, name := range strings.Split(path, ".") {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = obj[name]
+	}
+
+	return value
+}
+
+// timeValue returns the time of a TTL field, a number of seconds since epoch or a date string
+func timeValue(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		secs, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(0, int64(secs*float64(time.Second))), true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// merge returns the stored document with the fields of the patch, the nested objects are merged too
+func merge(stored, patch document) document {
+	for k, v := range patch {
+		storedObj, ok1 := stored[k].(map[string]interface{})
+		patchObj, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			stored[k] = map[string]interface{}(merge(storedObj, patchObj))
+			continue
+		}
+		stored[k] = v
+	}
+
+	return stored
+}
+
+func encode(doc interface{}) (document, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal document: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var res document
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("document must be an object: %w", err)
+	}
+	if res == nil {
+		return nil, fmt.Errorf("document must be an object")
+	}
+
+	return res, nil
+}
+
+func decode(doc document, result interface{}) error {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("unable to marshal document: %w", err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("unable to read document: %w", err)
+	}
+
+	return nil
+}
+
+func copyDocument(doc document) document {
+	data, _ := json.Marshal(doc)
+	res, _ := encode(json.RawMessage(data))
+	return res
+}
+
+func sortedKeys(docs map[string]document) []string {
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,126 @@
+package arangotest
+
+import (
+	"context"
+	"errors"
+	"github.com/kayx-org/freja/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type user struct {
+	component.Document
+	Email   string    `json:"email"`
+	Name    string    `json:"name,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func TestStoreCRUD(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	assert.NoError(t, s.CreateCollections(ctx, []component.Collection{{
+		Name:    "users",
+		Indexes: []component.Index{component.HashIndex{IxName: "ix_email", IxFields: []string{"email"}, Unique: true}},
+	}}))
+	users := component.NewRepository(s, "users", user{})
+
+	u := &user{Email: "a@test.com", Name: "a"}
+	assert.NoError(t, users.Create(ctx, u))
+	assert.NotEmpty(t, u.Key)
+	assert.Equal(t, "users/"+u.Key, u.ID)
+	assert.NotEmpty(t, u.Rev)
+
+	err := users.Create(ctx, &user{Email: "a@test.com"})
+	assert.True(t, errors.Is(err, component.ErrAlreadyExists))
+
+	stale := *u
+	assert.NoError(t, users.Update(ctx, &user{Document: component.Document{Key: u.Key}, Email: "b@test.com", Name: "b"}))
+	err = users.Replace(ctx, &stale)
+	assert.True(t, errors.Is(err, component.ErrRevisionConflict))
+
+	var got user
+	assert.NoError(t, users.Get(ctx, u.Key, &got))
+	assert.Equal(t, "b@test.com", got.Email)
+	assert.Equal(t, "b", got.Name)
+
+	upserted := &user{Document: component.Document{Key: u.Key}, Email: "c@test.com"}
+	assert.NoError(t, users.Upsert(ctx, upserted))
+	assert.Equal(t, "b", upserted.Name)
+
+	batch := []user{{Email: "d@test.com"}, {Email: "e@test.com"}}
+	assert.NoError(t, users.BatchCreate(ctx, batch))
+	assert.NotEmpty(t, batch[0].Key)
+
+	assert.NoError(t, users.Delete(ctx, u.Key, ""))
+	err = users.Get(ctx, u.Key, &got)
+	assert.True(t, errors.Is(err, component.ErrNotFound))
+	assert.Error(t, s.CreateDocument(ctx, "missing", &user{}))
+}
+
+func TestStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	s := NewStore()
+	s.Now = func() time.Time { return now }
+	assert.NoError(t, s.CreateCollections(ctx, []component.Collection{{
+		Name:    "sessions",
+		Indexes: []component.Index{component.TTLIndex{IxName: "ix_ttl", IxField: "created", ExpireAfter: 60}},
+	}}))
+
+	doc := &user{Document: component.Document{Key: "1"}, Created: now}
+	assert.NoError(t, s.CreateDocument(ctx, "sessions", doc))
+	assert.NoError(t, s.ReadDocument(ctx, "sessions", "1", &user{}))
+
+	now = now.Add(time.Minute)
+	err := s.ReadDocument(ctx, "sessions", "1", &user{})
+	assert.True(t, errors.Is(err, component.ErrNotFound))
+	assert.NoError(t, s.CreateDocument(ctx, "sessions", &user{Document: component.Document{Key: "1"}, Created: now}))
+}
+
+func TestStoreTraverse(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	assert.NoError(t, s.CreateGraph(ctx, &component.Graph{
+		Name:  "social",
+		Edges: []component.Edge{{Name: "follows", From: []string{"users"}, To: []string{"users"}}},
+	}))
+	for _, key := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, s.CreateDocument(ctx, "users", &user{Document: component.Document{Key: key}, Email: key}))
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"d", "a"}} {
+		edge := map[string]interface{}{"_from": "users/" + e[0], "_to": "users/" + e[1]}
+		assert.NoError(t, s.CreateDocument(ctx, "follows", &edge))
+	}
+	assert.Error(t, s.CreateDocument(ctx, "follows", &map[string]interface{}{"_from": "users/a"}))
+
+	testCases := map[string]struct {
+		direction component.EdgeDirection
+		min, max  int
+		expected  []string
+	}{
+		"outbound it should follow the edges from the vertex": {
+			direction: component.EdgeOutbound, min: 1, max: 5, expected: []string{"b", "c"},
+		},
+		"inbound it should follow the edges to the vertex": {
+			direction: component.EdgeInbound, min: 1, max: 1, expected: []string{"c", "d"},
+		},
+		"in any direction from depth 0 it should include the start vertex": {
+			direction: component.EdgeAny, min: 0, max: 1, expected: []string{"a", "b", "c", "d"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var vertices []user
+			err := s.Traverse(ctx, "users/a", []string{"follows"}, tc.direction, tc.min, tc.max, &vertices)
+
+			assert.NoError(t, err)
+			keys := make([]string, len(vertices))
+			for i, v := range vertices {
+				keys[i] = v.Key
+			}
+			assert.ElementsMatch(t, tc.expected, keys)
+		})
+	}
+}
@@ -2,7 +2,13 @@ package component
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/kayx-org/freja/env"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,26 +19,231 @@ const (
 	Mysql    DriverName = "mysql"
 )
 
-func openSql(dn DriverName, host string) (*sql.DB, error) {
+type OptionSQL func(*sqlPool)
+
+// sqlPool are the settings of the pool of connections, by default they are read from the env vars
+// DB_MAX_IDLE_CONN, DB_MAX_OPEN_CONN, DB_MAX_LIFETIME_CONN and DB_MAX_IDLE_TIME_CONN, the durations in seconds
+type sqlPool struct {
+	maxIdleConns    int
+	maxOpenConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+// OptionSQLMaxIdleConns sets how many idle connections are kept, by default 3
+func OptionSQLMaxIdleConns(n int) OptionSQL {
+	return func(p *sqlPool) {
+		p.maxIdleConns = n
+	}
+}
+
+// OptionSQLMaxOpenConns sets how many connections can be open, by default 3
+func OptionSQLMaxOpenConns(n int) OptionSQL {
+	return func(p *sqlPool) {
+		p.maxOpenConns = n
+	}
+}
+
+// OptionSQLConnMaxLifetime sets how long a connection is reused before it's closed, by default 5 minutes
+func OptionSQLConnMaxLifetime(t time.Duration) OptionSQL {
+	return func(p *sqlPool) {
+		p.connMaxLifetime = t
+	}
+}
+
+// OptionSQLConnMaxIdleTime sets how long a connection can be idle before it's closed, by default 1 minute
+func OptionSQLConnMaxIdleTime(t time.Duration) OptionSQL {
+	return func(p *sqlPool) {
+		p.connMaxIdleTime = t
+	}
+}
+
+func openSql(dn DriverName, host string, options ...OptionSQL) (*sql.DB, error) {
+	if err := checkSQLDriver(dn); err != nil {
+		return nil, err
+	}
 	db, err := sql.Open(string(dn), host)
 	if err != nil {
 		return nil, err
 	}
 
-	db.SetMaxIdleConns(env.GetEnvAsInt("DB_MAX_IDLE_CONN", 3))
-
-	db.SetMaxOpenConns(env.GetEnvAsInt("DB_MAX_OPEN_CONN", 3))
+	p := &sqlPool{
+		maxIdleConns:    env.GetEnvAsInt("DB_MAX_IDLE_CONN", 3),
+		maxOpenConns:    env.GetEnvAsInt("DB_MAX_OPEN_CONN", 3),
+		connMaxLifetime: time.Second * time.Duration(env.GetEnvAsInt("DB_MAX_LIFETIME_CONN", 300)),
+		connMaxIdleTime: time.Second * time.Duration(env.GetEnvAsInt("DB_MAX_IDLE_TIME_CONN", 60)),
+	}
+	for _, o := range options {
+		o(p)
+	}
 
-	maxLife := env.GetEnvAsInt("DB_MAX_LIFETIME_CONN", 1)
-	db.SetConnMaxLifetime(time.Second * time.Duration(maxLife))
+	db.SetMaxIdleConns(p.maxIdleConns)
+	db.SetMaxOpenConns(p.maxOpenConns)
+	db.SetConnMaxLifetime(p.connMaxLifetime)
+	db.SetConnMaxIdleTime(p.connMaxIdleTime)
 
 	return db, nil
 }
 
-func NewPSQL(host string) (*sql.DB, error) {
-	return openSql(Postgres, host)
+// NewSQL opens a pool of connections of any driver, it must be registered by importing its package
+func NewSQL(dn DriverName, dsn string, options ...OptionSQL) (*sql.DB, error) {
+	return openSql(dn, dsn, options...)
+}
+
+func NewPSQL(host string, options ...OptionSQL) (*sql.DB, error) {
+	return openSql(Postgres, host, options...)
+}
+
+func NewMYSQL(host string, options ...OptionSQL) (*sql.DB, error) {
+	return openSql(Mysql, host, options...)
+}
+
+// NewSQLite opens a pool of connections of github.com/mattn/go-sqlite3, the path can be file::memory:?cache=shared
+// to use an in-memory database shared by the connections of the pool
+func NewSQLite(path string, options ...OptionSQL) (*sql.DB, error) {
+	return openSql(SQLite, path, options...)
+}
+
+// DSN are the fields of the data source name of a database, it's built for each driver with String
+type DSN struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	// SSLMode is the sslmode of Postgres: disable, require, verify-ca or verify-full,
+	// for MySQL it's translated to the tls parameter and for SQL Server to the encrypt one
+	SSLMode string
+	// Params are other parameters of the driver, like connect_timeout or parseTime
+	Params map[string]string
+}
+
+// DSNFromEnv reads the DSN from the env vars DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME and DB_SSLMODE
+func DSNFromEnv() DSN {
+	return DSN{
+		Host:     env.GetEnv("DB_HOST", "localhost"),
+		Port:     env.GetEnvAsInt("DB_PORT", 0),
+		User:     env.GetEnv("DB_USER", ""),
+		Password: env.GetEnv("DB_PASSWORD", ""),
+		Database: env.GetEnv("DB_NAME", ""),
+		SSLMode:  env.GetEnv("DB_SSLMODE", ""),
+	}
 }
 
-func NewMYSQL(host string) (*sql.DB, error) {
-	return openSql(Mysql, host)
+// String returns the DSN in the format of the driver, the port is the default one of the database if it's not set
+func (d DSN) String(dn DriverName) (string, error) {
+	switch dn {
+	case Postgres, PostgresPgx:
+		return d.postgres(), nil
+	case SQLServer:
+		return d.sqlserver(), nil
+	case Mysql:
+		return d.mysql(), nil
+	default:
+		return "", fmt.Errorf("DSN not supported for driver '%s'", dn)
+	}
+}
+
+func (d DSN) postgres() string {
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   d.hostPort(5432),
+		Path:   "/" + d.Database,
+	}
+	if d.User != "" {
+		u.User = url.UserPassword(d.User, d.Password)
+	}
+
+	q := url.Values{}
+	for k, v := range d.Params {
+		q.Set(k, v)
+	}
+	if d.SSLMode != "" {
+		q.Set("sslmode", d.SSLMode)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func (d DSN) sqlserver() string {
+	u := url.URL{
+		Scheme: "sqlserver",
+		Host:   d.hostPort(1433),
+	}
+	if d.User != "" {
+		u.User = url.UserPassword(d.User, d.Password)
+	}
+
+	q := url.Values{}
+	for k, v := range d.Params {
+		q.Set(k, v)
+	}
+	if d.Database != "" {
+		q.Set("database", d.Database)
+	}
+	switch d.SSLMode {
+	case "":
+	case "disable":
+		q.Set("encrypt", "disable")
+	case "require":
+		q.Set("encrypt", "true")
+		q.Set("TrustServerCertificate", "true")
+	default:
+		q.Set("encrypt", "true")
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func (d DSN) mysql() string {
+	var b strings.Builder
+	if d.User != "" {
+		b.WriteString(d.User)
+		if d.Password != "" {
+			b.WriteString(":" + d.Password)
+		}
+		b.WriteString("@")
+	}
+	b.WriteString("tcp(" + d.hostPort(3306) + ")/" + d.Database)
+
+	params := make(map[string]string, len(d.Params)+1)
+	for k, v := range d.Params {
+		params[k] = v
+	}
+	switch d.SSLMode {
+	case "":
+	case "disable":
+		params["tls"] = "false"
+	case "require":
+		params["tls"] = "skip-verify"
+	default:
+		params["tls"] = "true"
+	}
+	if len(params) > 0 {
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			sep := "&"
+			if i == 0 {
+				sep = "?"
+			}
+			b.WriteString(sep + k + "=" + url.QueryEscape(params[k]))
+		}
+	}
+
+	return b.String()
+}
+
+func (d DSN) hostPort(defaultPort int) string {
+	port := d.Port
+	if port == 0 {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(d.Host, strconv.Itoa(port))
 }
@@ -0,0 +1,58 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDSN(t *testing.T) {
+	testCases := map[string]struct {
+		dsn         DSN
+		driver      DriverName
+		expected    string
+		expectedErr string
+	}{
+		"it should build the Postgres URL with the default port": {
+			dsn:      DSN{Host: "db", User: "app", Password: "p@ss", Database: "users", SSLMode: "require"},
+			driver:   Postgres,
+			expected: "postgres://app:p%40ss@db:5432/users?sslmode=require",
+		},
+		"it should build the Postgres URL with the params": {
+			dsn:      DSN{Host: "db", Port: 6432, Database: "users", Params: map[string]string{"connect_timeout": "5"}},
+			driver:   Postgres,
+			expected: "postgres://db:6432/users?connect_timeout=5",
+		},
+		"it should build the MySQL DSN translating the sslmode": {
+			dsn:      DSN{Host: "db", User: "app", Password: "secret", Database: "users", SSLMode: "verify-full", Params: map[string]string{"parseTime": "true"}},
+			driver:   Mysql,
+			expected: "app:secret@tcp(db:3306)/users?parseTime=true&tls=true",
+		},
+		"it should build the MySQL DSN without user": {
+			dsn:      DSN{Host: "::1", Port: 3307, Database: "users"},
+			driver:   Mysql,
+			expected: "tcp([::1]:3307)/users",
+		},
+		"it should build the SQL Server URL with the database as a param": {
+			dsn:      DSN{Host: "db", User: "sa", Password: "secret", Database: "users", SSLMode: "disable"},
+			driver:   SQLServer,
+			expected: "sqlserver://sa:secret@db:1433?database=users&encrypt=disable",
+		},
+		"if the driver is not supported it should fail": {
+			driver:      "oracle",
+			expectedErr: "DSN not supported for driver 'oracle'",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dsn, err := tc.dsn.String(tc.driver)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, dsn)
+		})
+	}
+}
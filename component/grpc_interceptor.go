@@ -0,0 +1,282 @@
+package component
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RequestIDMetadataKey is the metadata key of the request ID, it's read from the incoming metadata
+// and sent back in the header of the response
+const RequestIDMetadataKey = "x-request-id"
+
+// maxRequestIDLength is the length of the longest request ID taken from the metadata
+const maxRequestIDLength = 128
+
+// GRPCLogger is the part of freja.Logger used by the interceptors
+type GRPCLogger interface {
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type requestIDKey struct{}
+
+// RequestID returns the request ID of the context of a call, set by the request ID interceptors
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryRequestIDInterceptor takes the request ID of the metadata or generates one, it's added to the context
+// of the call and to the header of the response. The IDs of the metadata longer than 128 characters or with
+// characters that aren't printable ASCII are replaced, since they end up in the logs
+func UnaryRequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, RequestID(ctx)))
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequestIDInterceptor is the stream version of UnaryRequestIDInterceptor
+func StreamRequestIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, RequestID(ctx)))
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDMetadataKey); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if !validRequestID(id) {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UnaryLoggingInterceptor logs every call with its method, code, duration and request ID,
+// the errors of the server, like Internal or Unknown, are logged as errors and the rest of them as warnings
+func UnaryLoggingInterceptor(logger GRPCLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamLoggingInterceptor is the stream version of UnaryLoggingInterceptor
+func StreamLoggingInterceptor(logger GRPCLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), logger, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger GRPCLogger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	format := "grpc call %s code=%s duration=%s requestID=%s"
+	args := []interface{}{method, code, time.Since(start), RequestID(ctx)}
+	switch {
+	case err == nil:
+		logger.Infof(format, args...)
+	case isServerError(code):
+		logger.Errorf(format+" error=%v", append(args, err)...)
+	default:
+		logger.Warnf(format+" error=%v", append(args, err)...)
+	}
+}
+
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unimplemented, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnaryRecoveryInterceptor turns the panics of the handlers into Internal errors, logging them with their stack,
+// so a panic doesn't bring down the server
+func UnaryRecoveryInterceptor(logger GRPCLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, logger, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor is the stream version of UnaryRecoveryInterceptor
+func StreamRecoveryInterceptor(logger GRPCLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), logger, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, logger GRPCLogger, method string, r interface{}) error {
+	logger.Errorf("grpc call %s panicked requestID=%s: %v\n%s", method, RequestID(ctx), r, debug.Stack())
+	return status.Error(codes.Internal, "internal error")
+}
+
+// UnaryDeadlineInterceptor sets a deadline to the calls whose clients didn't send one
+func UnaryDeadlineInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := ctx.Deadline(); ok {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamDeadlineInterceptor is the stream version of UnaryDeadlineInterceptor
+func StreamDeadlineInterceptor(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := ss.Context().Deadline(); ok {
+			return handler(srv, ss)
+		}
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of a stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// GRPCMethodStats are the metrics of a method since the server started
+type GRPCMethodStats struct {
+	Method       string
+	Calls        int64
+	Errors       map[codes.Code]int64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// GRPCMetrics counts the calls, errors by code and latency of every method, the stats can be read with
+// Stats to be exported as metrics
+type GRPCMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*GRPCMethodStats
+}
+
+func NewGRPCMetrics() *GRPCMetrics {
+	return &GRPCMetrics{methods: make(map[string]*GRPCMethodStats)}
+}
+
+func (m *GRPCMetrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.record(info.FullMethod, time.Since(start), err)
+
+		return resp, err
+	}
+}
+
+func (m *GRPCMetrics) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.record(info.FullMethod, time.Since(start), err)
+
+		return err
+	}
+}
+
+func (m *GRPCMetrics) record(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.methods[method]
+	if !ok {
+		s = &GRPCMethodStats{Method: method, Errors: make(map[codes.Code]int64)}
+		m.methods[method] = s
+	}
+	s.Calls++
+	s.TotalLatency += latency
+	if latency > s.MaxLatency {
+		s.MaxLatency = latency
+	}
+	if err != nil {
+		s.Errors[status.Code(err)]++
+	}
+}
+
+// Stats returns a copy of the stats of the methods called, sorted by method
+func (m *GRPCMetrics) Stats() []GRPCMethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]GRPCMethodStats, 0, len(m.methods))
+	for _, s := range m.methods {
+		c := *s
+		c.Errors = make(map[codes.Code]int64, len(s.Errors))
+		for code, n := range s.Errors {
+			c.Errors[code] = n
+		}
+		stats = append(stats, c)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Method < stats[j].Method
+	})
+
+	return stats
+}
@@ -0,0 +1,174 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordLogger struct {
+	levels []string
+}
+
+func (l *recordLogger) Infof(string, ...interface{})  { l.levels = append(l.levels, "info") }
+func (l *recordLogger) Warnf(string, ...interface{})  { l.levels = append(l.levels, "warn") }
+func (l *recordLogger) Errorf(string, ...interface{}) { l.levels = append(l.levels, "error") }
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestGRPCUnaryInterceptors(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}
+	testCases := map[string]struct {
+		handler        grpc.UnaryHandler
+		expectedCode   codes.Code
+		expectedLevels []string
+	}{
+		"if the handler succeeds it should log it as info": {
+			handler: func(context.Context, interface{}) (interface{}, error) {
+				return "ok", nil
+			},
+			expectedCode:   codes.OK,
+			expectedLevels: []string{"info"},
+		},
+		"if the handler fails because of the client it should log it as a warning": {
+			handler: func(context.Context, interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "user not found")
+			},
+			expectedCode:   codes.NotFound,
+			expectedLevels: []string{"warn"},
+		},
+		"if the handler panics it should return an internal error": {
+			handler: func(context.Context, interface{}) (interface{}, error) {
+				panic("test")
+			},
+			expectedCode:   codes.Internal,
+			expectedLevels: []string{"error", "error"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			logger := &recordLogger{}
+			metrics := NewGRPCMetrics()
+			chain := []grpc.UnaryServerInterceptor{
+				UnaryLoggingInterceptor(logger), metrics.UnaryInterceptor(), UnaryRecoveryInterceptor(logger),
+			}
+			handler := tc.handler
+			for i := len(chain) - 1; i >= 0; i-- {
+				interceptor, next := chain[i], handler
+				handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+					return interceptor(ctx, req, info, next)
+				}
+			}
+
+			_, err := handler(context.Background(), nil)
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			assert.Equal(t, tc.expectedLevels, logger.levels)
+			stats := metrics.Stats()
+			assert.Len(t, stats, 1)
+			assert.Equal(t, info.FullMethod, stats[0].Method)
+			assert.Equal(t, int64(1), stats[0].Calls)
+			if tc.expectedCode != codes.OK {
+				assert.Equal(t, map[codes.Code]int64{tc.expectedCode: 1}, stats[0].Errors)
+			}
+		})
+	}
+}
+
+func TestGRPCRequestIDInterceptor(t *testing.T) {
+	testCases := map[string]struct {
+		md       metadata.MD
+		expected string
+	}{
+		"if the client sends a request ID it should be propagated": {
+			md:       metadata.Pairs(RequestIDMetadataKey, "abc"),
+			expected: "abc",
+		},
+		"if the client doesn't send a request ID it should be generated": {
+			md: metadata.MD{},
+		},
+		"if the request ID is too long it should be generated": {
+			md: metadata.Pairs(RequestIDMetadataKey, strings.Repeat("a", 129)),
+		},
+		"if the request ID has non-printable characters it should be generated": {
+			md: metadata.Pairs(RequestIDMetadataKey, "abc\ninjected"),
+		},
+		"if the request ID has the maximum length it should be propagated": {
+			md:       metadata.Pairs(RequestIDMetadataKey, strings.Repeat("a", 128)),
+			expected: strings.Repeat("a", 128),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ss := &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), tc.md)}
+			var id string
+			err := StreamRequestIDInterceptor()(nil, ss, &grpc.StreamServerInfo{}, func(_ interface{}, stream grpc.ServerStream) error {
+				id = RequestID(stream.Context())
+				return nil
+			})
+
+			assert.NoError(t, err)
+			if tc.expected != "" {
+				assert.Equal(t, tc.expected, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+			assert.Equal(t, []string{id}, ss.header.Get(RequestIDMetadataKey))
+		})
+	}
+}
+
+func TestGRPCDeadlineInterceptor(t *testing.T) {
+	withDeadline, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	testCases := map[string]struct {
+		ctx      context.Context
+		expected time.Duration
+	}{
+		"if the client doesn't send a deadline it should set the default one": {
+			ctx:      context.Background(),
+			expected: time.Second,
+		},
+		"if the client sends a deadline it should keep it": {
+			ctx:      withDeadline,
+			expected: time.Hour,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnaryDeadlineInterceptor(time.Second)(tc.ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				deadline, ok := ctx.Deadline()
+				if !ok {
+					return nil, fmt.Errorf("no deadline")
+				}
+				assert.InDelta(t, tc.expected, time.Until(deadline), float64(time.Second/2))
+				return nil, nil
+			})
+
+			assert.NoError(t, err)
+		})
+	}
+}
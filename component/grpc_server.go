@@ -7,22 +7,71 @@ import (
 	"time"
 )
 
+type OptionGRPCServer func(*GRPCServer)
+
 type GRPCServer struct {
-	port   string
-	addr   string
-	server *grpc.Server
+	port    string
+	addr    string
+	server  *grpc.Server
+	options []grpc.ServerOption
+	unary   []grpc.UnaryServerInterceptor
+	stream  []grpc.StreamServerInterceptor
+}
+
+func NewGRPCServer(options ...OptionGRPCServer) *GRPCServer {
+	s := &GRPCServer{
+		addr:    env.GetEnv("GRPC_SERVICE_ADDR", "0.0.0.0"),
+		port:    env.GetEnv("GRPC_SERVICE_PORT", "50051"),
+		options: []grpc.ServerOption{grpc.ConnectionTimeout(time.Second * 10)},
+	}
+	for _, o := range options {
+		o(s)
+	}
+
+	opts := append(s.options, grpc.ChainUnaryInterceptor(s.unary...), grpc.ChainStreamInterceptor(s.stream...))
+	s.server = grpc.NewServer(opts...)
+
+	return s
 }
 
-func NewGRPCServer() *GRPCServer {
-	addr := env.GetEnv("GRPC_SERVICE_ADDR", "0.0.0.0")
-	port := env.GetEnv("GRPC_SERVICE_PORT", "50051")
+// OptionGRPCServerOptions adds options to the grpc.Server, like the keepalive or the credentials,
+// they are applied after the default connection timeout of 10 seconds so they can override it
+func OptionGRPCServerOptions(opts ...grpc.ServerOption) OptionGRPCServer {
+	return func(s *GRPCServer) {
+		s.options = append(s.options, opts...)
+	}
+}
 
-	s := grpc.NewServer(grpc.ConnectionTimeout(time.Second * 10))
+// OptionGRPCUnaryInterceptors adds interceptors to the unary calls, the first one is the outermost
+func OptionGRPCUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) OptionGRPCServer {
+	return func(s *GRPCServer) {
+		s.unary = append(s.unary, interceptors...)
+	}
+}
+
+// OptionGRPCStreamInterceptors adds interceptors to the streams, the first one is the outermost
+func OptionGRPCStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) OptionGRPCServer {
+	return func(s *GRPCServer) {
+		s.stream = append(s.stream, interceptors...)
+	}
+}
 
-	return &GRPCServer{
-		addr:   addr,
-		port:   port,
-		server: s,
+// OptionGRPCInterceptors adds the interceptors shipped with the server in this order: request ID, logging, metrics,
+// recovery and default deadline. The metrics can be nil, and the deadline is only set if it's greater than zero
+func OptionGRPCInterceptors(logger GRPCLogger, metrics *GRPCMetrics, deadline time.Duration) OptionGRPCServer {
+	return func(s *GRPCServer) {
+		s.unary = append(s.unary, UnaryRequestIDInterceptor(), UnaryLoggingInterceptor(logger))
+		s.stream = append(s.stream, StreamRequestIDInterceptor(), StreamLoggingInterceptor(logger))
+		if metrics != nil {
+			s.unary = append(s.unary, metrics.UnaryInterceptor())
+			s.stream = append(s.stream, metrics.StreamInterceptor())
+		}
+		s.unary = append(s.unary, UnaryRecoveryInterceptor(logger))
+		s.stream = append(s.stream, StreamRecoveryInterceptor(logger))
+		if deadline > 0 {
+			s.unary = append(s.unary, UnaryDeadlineInterceptor(deadline))
+			s.stream = append(s.stream, StreamDeadlineInterceptor(deadline))
+		}
 	}
 }
 
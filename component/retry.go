@@ -0,0 +1,30 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// retryWithBackoff calls fn until it succeeds, fails with an error that is not retryable or runs out of retries.
+// The wait between attempts starts at backoff and doubles up to max
+func retryWithBackoff(ctx context.Context, retries int, backoff, max time.Duration, retryable func(error) bool, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+
+		// the jitter avoids that the transactions in conflict retry at the same time
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transaction canceled while waiting to retry: %w", err)
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+}
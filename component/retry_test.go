@@ -0,0 +1,70 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryWithBackoff(t *testing.T) {
+	errRetryable := errors.New("conflict")
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := map[string]struct {
+		ctx           context.Context
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		"if it succeeds it shouldn't be retried": {
+			ctx:           context.Background(),
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		"a retryable error should be retried until it succeeds": {
+			ctx:           context.Background(),
+			errs:          []error{errRetryable, errRetryable, nil},
+			expectedCalls: 3,
+		},
+		"a retryable error should be returned when there are no retries left": {
+			ctx:           context.Background(),
+			errs:          []error{errRetryable, errRetryable, errRetryable, errRetryable},
+			expectedCalls: 3,
+			expectedErr:   errRetryable,
+		},
+		"an error that is not retryable should be returned": {
+			ctx:           context.Background(),
+			errs:          []error{errors.New("syntax")},
+			expectedCalls: 1,
+			expectedErr:   errors.New("syntax"),
+		},
+		"if the context is canceled it shouldn't wait to retry": {
+			ctx:           canceled,
+			errs:          []error{errRetryable, nil},
+			expectedCalls: 1,
+			expectedErr:   errRetryable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := retryWithBackoff(tc.ctx, 2, time.Millisecond, 2*time.Millisecond, func(err error) bool {
+				return err == errRetryable
+			}, func() error {
+				calls++
+				return tc.errs[calls-1]
+			})
+
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Contains(t, err.Error(), tc.expectedErr.Error())
+			}
+		})
+	}
+}
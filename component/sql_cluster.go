@@ -0,0 +1,189 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+// ReplicaBalancing is how the reads are spread among the healthy replicas
+type ReplicaBalancing int
+
+const (
+	// RoundRobin uses the replicas in turns
+	RoundRobin ReplicaBalancing = iota
+	// LeastConnections uses the replica with fewer connections in use
+	LeastConnections
+)
+
+type OptionSQLCluster func(*SQLCluster)
+
+// SQLCluster is a writer pool for the primary and a reader pool for every replica. The writes and the transactions
+// go to the primary, the reads and the read-only transactions go to a healthy replica, and when none of them is
+// healthy to the primary. The replicas are healthy until they are reported otherwise with SetReplicaHealthy,
+// which the DB cluster middleware does with the result of its checks.
+//
+// QueryContext and QueryRowContext read from a replica, which can lag behind the primary. The reads that must see
+// the writes just made, and the statements that write and return rows like INSERT ... RETURNING, have to use
+// QueryPrimaryContext and QueryRowPrimaryContext
+type SQLCluster struct {
+	writer    *sql.DB
+	readers   []*sqlReplica
+	balancing ReplicaBalancing
+	next      uint32
+}
+
+type sqlReplica struct {
+	db   *sql.DB
+	down int32
+}
+
+func NewSQLCluster(writer *sql.DB, readers []*sql.DB, options ...OptionSQLCluster) *SQLCluster {
+	c := &SQLCluster{writer: writer}
+	for _, db := range readers {
+		c.readers = append(c.readers, &sqlReplica{db: db})
+	}
+	for _, o := range options {
+		o(c)
+	}
+
+	return c
+}
+
+// NewPSQLCluster opens the pools of the Postgres primary and its replicas, the options are applied to all of them
+func NewPSQLCluster(primary string, replicas []string, options ...OptionSQL) (*SQLCluster, error) {
+	return openSqlCluster(Postgres, primary, replicas, options...)
+}
+
+// NewMYSQLCluster opens the pools of the MySQL primary and its replicas, the options are applied to all of them
+func NewMYSQLCluster(primary string, replicas []string, options ...OptionSQL) (*SQLCluster, error) {
+	return openSqlCluster(Mysql, primary, replicas, options...)
+}
+
+func openSqlCluster(dn DriverName, primary string, replicas []string, options ...OptionSQL) (*SQLCluster, error) {
+	writer, err := openSql(dn, primary, options...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open primary: %w", err)
+	}
+
+	readers := make([]*sql.DB, 0, len(replicas))
+	for i, host := range replicas {
+		db, err := openSql(dn, host, options...)
+		if err != nil {
+			writer.Close()
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, fmt.Errorf("unable to open replica %d: %w", i, err)
+		}
+		readers = append(readers, db)
+	}
+
+	return NewSQLCluster(writer, readers), nil
+}
+
+func OptionReplicaBalancing(b ReplicaBalancing) OptionSQLCluster {
+	return func(c *SQLCluster) {
+		c.balancing = b
+	}
+}
+
+// Writer returns the pool of the primary
+func (c *SQLCluster) Writer() *sql.DB {
+	return c.writer
+}
+
+// Readers returns the pools of the replicas, healthy or not
+func (c *SQLCluster) Readers() []*sql.DB {
+	readers := make([]*sql.DB, len(c.readers))
+	for i, r := range c.readers {
+		readers[i] = r.db
+	}
+
+	return readers
+}
+
+// Reader returns the pool of a healthy replica, or the one of the primary if there are none
+func (c *SQLCluster) Reader() *sql.DB {
+	n := len(c.readers)
+	if n == 0 {
+		return c.writer
+	}
+
+	start := int((atomic.AddUint32(&c.next, 1) - 1) % uint32(n))
+	var chosen *sql.DB
+	least := 0
+	for i := 0; i < n; i++ {
+		r := c.readers[(start+i)%n]
+		if atomic.LoadInt32(&r.down) == 1 {
+			continue
+		}
+		if c.balancing == RoundRobin {
+			return r.db
+		}
+		if inUse := r.db.Stats().InUse; chosen == nil || inUse < least {
+			chosen, least = r.db, inUse
+		}
+	}
+	if chosen == nil {
+		return c.writer
+	}
+
+	return chosen
+}
+
+// SetReplicaHealthy sets whether the replica at index i of Readers can receive reads
+func (c *SQLCluster) SetReplicaHealthy(i int, healthy bool) {
+	var down int32
+	if !healthy {
+		down = 1
+	}
+	atomic.StoreInt32(&c.readers[i].down, down)
+}
+
+// ExecContext runs the statement in the primary
+func (c *SQLCluster) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.writer.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs the query in a replica, bear in mind they can lag behind the primary
+func (c *SQLCluster) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.Reader().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs the query in a replica, bear in mind they can lag behind the primary
+func (c *SQLCluster) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.Reader().QueryRowContext(ctx, query, args...)
+}
+
+// QueryPrimaryContext runs the query in the primary
+func (c *SQLCluster) QueryPrimaryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.writer.QueryContext(ctx, query, args...)
+}
+
+// QueryRowPrimaryContext runs the query in the primary
+func (c *SQLCluster) QueryRowPrimaryContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.writer.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts the transaction in a replica if it's read-only, otherwise in the primary
+func (c *SQLCluster) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if opts != nil && opts.ReadOnly {
+		return c.Reader().BeginTx(ctx, opts)
+	}
+
+	return c.writer.BeginTx(ctx, opts)
+}
+
+// Close closes the pools of the primary and the replicas, and returns the first error
+func (c *SQLCluster) Close() error {
+	err := c.writer.Close()
+	for _, r := range c.readers {
+		if rErr := r.db.Close(); err == nil {
+			err = rErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newStubDBs(t *testing.T, n int) []*sql.DB {
+	dbs := make([]*sql.DB, n)
+	for i := range dbs {
+		db, err := sql.Open("stub", "")
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		dbs[i] = db
+	}
+
+	return dbs
+}
+
+func TestSQLClusterReader(t *testing.T) {
+	testCases := map[string]struct {
+		replicas  int
+		down      []int
+		balancing ReplicaBalancing
+		inUse     []int
+		expected  []int
+	}{
+		"without replicas it should read from the primary": {
+			expected: []int{0, 0},
+		},
+		"it should use the replicas in turns": {
+			replicas: 2,
+			expected: []int{1, 2, 1},
+		},
+		"it should skip the replicas that are down": {
+			replicas: 3,
+			down:     []int{1},
+			expected: []int{1, 3, 3, 1},
+		},
+		"if all the replicas are down it should read from the primary": {
+			replicas: 2,
+			down:     []int{0, 1},
+			expected: []int{0, 0},
+		},
+		"it should use the replica with fewer connections in use": {
+			replicas:  3,
+			balancing: LeastConnections,
+			inUse:     []int{0, 2},
+			expected:  []int{2, 2},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbs := newStubDBs(t, tc.replicas+1)
+			cluster := NewSQLCluster(dbs[0], dbs[1:], OptionReplicaBalancing(tc.balancing))
+			for _, i := range tc.down {
+				cluster.SetReplicaHealthy(i, false)
+			}
+			for _, i := range tc.inUse {
+				conn, err := dbs[i+1].Conn(context.Background())
+				assert.NoError(t, err)
+				defer conn.Close()
+			}
+
+			for _, expected := range tc.expected {
+				assert.Same(t, dbs[expected], cluster.Reader())
+			}
+		})
+	}
+}
+
+func TestSQLClusterReplicaRecovery(t *testing.T) {
+	dbs := newStubDBs(t, 2)
+	cluster := NewSQLCluster(dbs[0], dbs[1:])
+
+	cluster.SetReplicaHealthy(0, false)
+	assert.Same(t, dbs[0], cluster.Reader())
+
+	cluster.SetReplicaHealthy(0, true)
+	assert.Same(t, dbs[1], cluster.Reader())
+	assert.Same(t, dbs[0], cluster.Writer())
+}
+
+func TestSQLClusterQuery(t *testing.T) {
+	testCases := map[string]struct {
+		query    func(c *SQLCluster) (*sql.Rows, error)
+		expected int
+	}{
+		"QueryContext should read from a replica": {
+			query: func(c *SQLCluster) (*sql.Rows, error) {
+				return c.QueryContext(context.Background(), "SELECT 1")
+			},
+			expected: 1,
+		},
+		"QueryPrimaryContext should read from the primary": {
+			query: func(c *SQLCluster) (*sql.Rows, error) {
+				return c.QueryPrimaryContext(context.Background(), "SELECT 1")
+			},
+			expected: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbs := newStubDBs(t, 2)
+			cluster := NewSQLCluster(dbs[0], dbs[1:])
+
+			rows, err := tc.query(cluster)
+
+			assert.NoError(t, err)
+			for i, db := range dbs {
+				if i == tc.expected {
+					assert.Equal(t, 1, db.Stats().InUse)
+				} else {
+					assert.Equal(t, 0, db.Stats().InUse)
+				}
+			}
+			assert.NoError(t, rows.Close())
+		})
+	}
+}
+
+func TestOpenSQLCluster(t *testing.T) {
+	cluster, err := openSqlCluster("stub", "primary", []string{"replica1", "replica2"}, OptionSQLMaxOpenConns(7))
+	assert.NoError(t, err)
+
+	dbs := append([]*sql.DB{cluster.Writer()}, cluster.Readers()...)
+	assert.Len(t, dbs, 3)
+	for _, db := range dbs {
+		assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+		assert.NoError(t, db.Close())
+	}
+}
@@ -0,0 +1,118 @@
+package component
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+const (
+	// PostgresPgx is the name of the database/sql driver of github.com/jackc/pgx/v4/stdlib
+	PostgresPgx DriverName = "pgx"
+	// SQLite is the name of the driver of github.com/mattn/go-sqlite3, it can be used in the tests
+	// with an in-memory database like file::memory:?cache=shared
+	SQLite DriverName = "sqlite3"
+	// SQLiteGo is the name of the driver of modernc.org/sqlite, which doesn't need cgo
+	SQLiteGo DriverName = "sqlite"
+	// SQLServer is the name of the driver of github.com/denisenkom/go-mssqldb
+	SQLServer DriverName = "sqlserver"
+	// Oracle is the name of the driver of github.com/godror/godror
+	Oracle DriverName = "godror"
+)
+
+// SQLDialect tells how to use a driver, the ones of the DriverName constants are registered by default
+// and other drivers can be added with RegisterSQLDialect
+type SQLDialect struct {
+	Driver DriverName
+	// Import is the package that registers the driver, it's suggested when the driver isn't registered
+	Import string
+	// HealthQuery is run by the health checks besides the ping, it's "SELECT 1" if it's empty
+	HealthQuery string
+	// Placeholder returns the placeholder of the i-th parameter of a query starting at 1, it's "?" if it's nil
+	Placeholder func(i int) string
+	// MigrationsTable returns the statement that creates the table of the SQL migrations if it doesn't exist,
+	// with the columns version, name and applied_at. It's a CREATE TABLE IF NOT EXISTS if it's nil
+	MigrationsTable func(table string) string
+}
+
+var (
+	dialectsMutex sync.RWMutex
+	dialects      = map[DriverName]SQLDialect{
+		Postgres:    {Driver: Postgres, Import: "github.com/lib/pq", Placeholder: dollarPlaceholder},
+		PostgresPgx: {Driver: PostgresPgx, Import: "github.com/jackc/pgx/v4/stdlib", Placeholder: dollarPlaceholder},
+		Mysql:       {Driver: Mysql, Import: "github.com/go-sql-driver/mysql"},
+		SQLite:      {Driver: SQLite, Import: "github.com/mattn/go-sqlite3"},
+		SQLiteGo:    {Driver: SQLiteGo, Import: "modernc.org/sqlite"},
+		SQLServer: {Driver: SQLServer, Import: "github.com/denisenkom/go-mssqldb", Placeholder: func(i int) string {
+			return fmt.Sprintf("@p%d", i)
+		}, MigrationsTable: sqlServerMigrationsTable},
+		Oracle: {Driver: Oracle, Import: "github.com/godror/godror", HealthQuery: "SELECT 1 FROM DUAL", Placeholder: func(i int) string {
+			return fmt.Sprintf(":%d", i)
+		}},
+	}
+)
+
+// RegisterSQLDialect adds the dialect of a driver, replacing the one with the same name
+func RegisterSQLDialect(d SQLDialect) {
+	dialectsMutex.Lock()
+	defer dialectsMutex.Unlock()
+
+	dialects[d.Driver] = d
+}
+
+// LookupSQLDialect returns the dialect of the driver, the drivers without one get the defaults
+func LookupSQLDialect(dn DriverName) SQLDialect {
+	dialectsMutex.RLock()
+	d, ok := dialects[dn]
+	dialectsMutex.RUnlock()
+	if !ok {
+		d = SQLDialect{Driver: dn}
+	}
+	if d.HealthQuery == "" {
+		d.HealthQuery = "SELECT 1"
+	}
+	if d.Placeholder == nil {
+		d.Placeholder = questionPlaceholder
+	}
+	if d.MigrationsTable == nil {
+		d.MigrationsTable = defaultMigrationsTable
+	}
+
+	return d
+}
+
+// checkSQLDriver returns an error if the driver isn't registered in database/sql, so it fails when the
+// pool is created instead of when the first connection is opened
+func checkSQLDriver(dn DriverName) error {
+	for _, name := range sql.Drivers() {
+		if name == string(dn) {
+			return nil
+		}
+	}
+
+	if d := LookupSQLDialect(dn); d.Import != "" {
+		return fmt.Errorf("sql driver '%s' is not registered, import _ \"%s\"", dn, d.Import)
+	}
+
+	return fmt.Errorf("sql driver '%s' is not registered", dn)
+}
+
+func dollarPlaceholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+func questionPlaceholder(int) string {
+	return "?"
+}
+
+func defaultMigrationsTable(table string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s "+
+		"(version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)", table)
+}
+
+// sqlServerMigrationsTable checks the table with OBJECT_ID since T-SQL has no CREATE TABLE IF NOT EXISTS,
+// and uses DATETIME2 because TIMESTAMP is a rowversion there
+func sqlServerMigrationsTable(table string) string {
+	return fmt.Sprintf("IF OBJECT_ID(N'%s', N'U') IS NULL CREATE TABLE %s "+
+		"(version BIGINT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIME2 NOT NULL)", table, table)
+}
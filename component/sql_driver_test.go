@@ -0,0 +1,92 @@
+package component
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewSQL(t *testing.T) {
+	testCases := map[string]struct {
+		driver      DriverName
+		expectedErr string
+	}{
+		"if the driver is registered it should open the pool": {
+			driver: "stub",
+		},
+		"if a known driver is not registered it should suggest its package": {
+			driver:      SQLServer,
+			expectedErr: "sql driver 'sqlserver' is not registered, import _ \"github.com/denisenkom/go-mssqldb\"",
+		},
+		"if an unknown driver is not registered it should fail": {
+			driver:      "foo",
+			expectedErr: "sql driver 'foo' is not registered",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db, err := NewSQL(tc.driver, "", OptionSQLMaxOpenConns(7))
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+			db.Close()
+		})
+	}
+}
+
+func TestLookupSQLDialect(t *testing.T) {
+	dialectsMutex.RLock()
+	previous, registered := dialects["stub"]
+	dialectsMutex.RUnlock()
+	t.Cleanup(func() {
+		dialectsMutex.Lock()
+		defer dialectsMutex.Unlock()
+		if registered {
+			dialects["stub"] = previous
+		} else {
+			delete(dialects, "stub")
+		}
+	})
+	RegisterSQLDialect(SQLDialect{Driver: "stub", HealthQuery: "PRAGMA quick_check"})
+
+	testCases := map[string]struct {
+		driver              DriverName
+		expectedQuery       string
+		expectedPlaceholder string
+	}{
+		"Postgres should use numbered placeholders": {
+			driver:              Postgres,
+			expectedQuery:       "SELECT 1",
+			expectedPlaceholder: "$2",
+		},
+		"Oracle should use its own health query": {
+			driver:              Oracle,
+			expectedQuery:       "SELECT 1 FROM DUAL",
+			expectedPlaceholder: ":2",
+		},
+		"a registered dialect should keep its health query": {
+			driver:              "stub",
+			expectedQuery:       "PRAGMA quick_check",
+			expectedPlaceholder: "?",
+		},
+		"an unknown driver should get the defaults": {
+			driver:              "foo",
+			expectedQuery:       "SELECT 1",
+			expectedPlaceholder: "?",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := LookupSQLDialect(tc.driver)
+
+			assert.Equal(t, tc.driver, d.Driver)
+			assert.Equal(t, tc.expectedQuery, d.HealthQuery)
+			assert.Equal(t, tc.expectedPlaceholder, d.Placeholder(2))
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLMigration is a versioned .sql file, the name of the file starts with the version followed by an underscore,
+// like 0001_create_users.sql
+type SQLMigration struct {
+	Version int64
+	Name    string
+	SQL     string
+}
+
+// AppliedSQLMigration is the row stored in the migrations table for every applied migration
+type AppliedSQLMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+type OptionSQLMigrator func(*SQLMigrator)
+
+// SQLMigrator applies the .sql files of a directory in ascending order of version, keeping track of the applied
+// versions in a table. Every migration runs in its own transaction with the insert of its version. An advisory lock,
+// an application lock in SQL Server, is held while migrating so only one replica runs the migrations, the rest wait
+// until it's released and then find nothing to apply, SQLite doesn't need it. It can be run from the Init of the
+// migration middleware or from a standalone command calling Up.
+//
+// Each file is sent to the database as a single statement. MySQL rejects several statements in one call unless
+// the DSN has multiStatements=true, like DSN.Params["multiStatements"] = "true". MySQL DDL isn't transactional
+// either, every DDL statement commits implicitly, so a migration that fails after one isn't rolled back and its
+// version isn't recorded, it has to be fixed by hand before running it again. Keeping one DDL statement per file
+// avoids it. The applied times are read as text when the DSN doesn't have parseTime=true
+type SQLMigrator struct {
+	db      *sql.DB
+	driver  DriverName
+	files   fs.FS
+	dir     string
+	table   string
+	lockKey int64
+}
+
+func NewSQLMigrator(db *sql.DB, driver DriverName, files fs.FS, options ...OptionSQLMigrator) *SQLMigrator {
+	m := &SQLMigrator{
+		db:     db,
+		driver: driver,
+		files:  files,
+		dir:    ".",
+		table:  "schema_migrations",
+	}
+	for _, o := range options {
+		o(m)
+	}
+	if m.lockKey == 0 {
+		h := fnv.New64a()
+		h.Write([]byte(m.table))
+		m.lockKey = int64(h.Sum64() >> 1)
+	}
+
+	return m
+}
+
+// OptionSQLMigrationsDir sets the directory of the files inside the fs, like the one of an embedded directory
+func OptionSQLMigrationsDir(dir string) OptionSQLMigrator {
+	return func(m *SQLMigrator) {
+		m.dir = dir
+	}
+}
+
+// OptionSQLMigrationsTable sets the table where the applied versions are stored, by default schema_migrations
+func OptionSQLMigrationsTable(name string) OptionSQLMigrator {
+	return func(m *SQLMigrator) {
+		m.table = name
+	}
+}
+
+// OptionSQLMigrationLockKey sets the key of the advisory lock, by default it's derived from the table name
+func OptionSQLMigrationLockKey(key int64) OptionSQLMigrator {
+	return func(m *SQLMigrator) {
+		m.lockKey = key
+	}
+}
+
+// Migrations returns the migrations of the directory sorted by version
+func (m *SQLMigrator) Migrations() ([]SQLMigration, error) {
+	entries, err := fs.ReadDir(m.files, m.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations dir '%s': %w", m.dir, err)
+	}
+
+	migrations := make([]SQLMigration, 0, len(entries))
+	versions := make(map[int64]string, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		sep := strings.Index(e.Name(), "_")
+		if sep < 1 {
+			return nil, fmt.Errorf("migration '%s' must start with its version and an underscore", e.Name())
+		}
+		version, err := strconv.ParseInt(e.Name()[:sep], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration '%s': %w", e.Name(), err)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations '%s' and '%s' have the same version", other, e.Name())
+		}
+		versions[version] = e.Name()
+
+		data, err := fs.ReadFile(m.files, path.Join(m.dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration '%s': %w", e.Name(), err)
+		}
+		migrations = append(migrations, SQLMigration{
+			Version: version,
+			Name:    strings.TrimSuffix(e.Name()[sep+1:], ".sql"),
+			SQL:     string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Applied returns the migrations applied sorted by version
+func (m *SQLMigrator) Applied(ctx context.Context) ([]AppliedSQLMigration, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+
+	return m.applied(ctx, m.db)
+}
+
+// Plan returns the migrations that haven't been applied yet
+func (m *SQLMigrator) Plan(ctx context.Context) ([]SQLMigration, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+
+	return m.plan(ctx, m.db)
+}
+
+// Up applies the pending migrations
+func (m *SQLMigrator) Up(ctx context.Context) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := m.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer m.unlock(conn)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	pending, err := m.plan(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range pending {
+		if err := m.apply(ctx, conn, mig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *SQLMigrator) apply(ctx context.Context, conn *sql.Conn, mig SQLMigration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin migration %d: %w", mig.Version, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, mig.SQL); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("unable to apply migration %d '%s': %w", mig.Version, mig.Name, err)
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (%s, %s, %s)",
+		m.table, m.placeholder(1), m.placeholder(2), m.placeholder(3))
+	if _, err := tx.ExecContext(ctx, insert, mig.Version, mig.Name, time.Now().UTC()); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("unable to record migration %d: %w", mig.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit migration %d: %w", mig.Version, err)
+	}
+
+	return nil
+}
+
+// sqlQuerier is the part of *sql.DB and *sql.Conn used to read the migrations table
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (m *SQLMigrator) ensureTable(ctx context.Context, q sqlQuerier) error {
+	if _, err := q.ExecContext(ctx, LookupSQLDialect(m.driver).MigrationsTable(m.table)); err != nil {
+		return fmt.Errorf("unable to create migrations table: %w", err)
+	}
+
+	return nil
+}
+
+func (m *SQLMigrator) applied(ctx context.Context, q sqlQuerier) ([]AppliedSQLMigration, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT version, name, applied_at FROM %s ORDER BY version", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []AppliedSQLMigration
+	for rows.Next() {
+		var a AppliedSQLMigration
+		var appliedAt sqlTime
+		if err := rows.Scan(&a.Version, &a.Name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("unable to read applied migration: %w", err)
+		}
+		a.AppliedAt = time.Time(appliedAt)
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// sqlTime scans a time returned as text, like the ones of MySQL when the DSN doesn't have parseTime=true
+// or the ones of some SQLite drivers, the times without zone are UTC as they are inserted
+type sqlTime time.Time
+
+var sqlTimeLayouts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano}
+
+func (t *sqlTime) Scan(v interface{}) error {
+	var s string
+	switch v := v.(type) {
+	case time.Time:
+		*t = sqlTime(v)
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("unsupported time type %T", v)
+	}
+
+	for _, layout := range sqlTimeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			*t = sqlTime(parsed)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to parse time '%s'", s)
+}
+
+func (m *SQLMigrator) plan(ctx context.Context, q sqlQuerier) ([]SQLMigration, error) {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingSQLMigrations(migrations, applied), nil
+}
+
+func pendingSQLMigrations(migrations []SQLMigration, applied []AppliedSQLMigration) []SQLMigration {
+	done := make(map[int64]bool, len(applied))
+	for _, a := range applied {
+		done[a.Version] = true
+	}
+
+	pending := make([]SQLMigration, 0, len(migrations))
+	for _, mig := range migrations {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+
+	return pending
+}
+
+// lock takes the advisory lock in the connection, it waits until the lock is free or the context is done.
+// SQLite has no advisory locks, its database is used by a single process and each migration locks it while writing
+func (m *SQLMigrator) lock(ctx context.Context, conn *sql.Conn) error {
+	var err error
+	switch m.driver {
+	case Postgres, PostgresPgx:
+		_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.lockKey)
+	case Mysql:
+		var ok sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", m.lockName()).Scan(&ok)
+		if err == nil && ok.Int64 != 1 {
+			err = fmt.Errorf("lock '%s' not granted", m.lockName())
+		}
+	case SQLServer:
+		// sp_getapplock returns 0 or 1 when the lock is granted and a negative number otherwise
+		var status int64
+		err = conn.QueryRowContext(ctx, "DECLARE @status INT; EXEC @status = sp_getapplock @Resource = @p1, "+
+			"@LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = -1; SELECT @status", m.lockName()).Scan(&status)
+		if err == nil && status < 0 {
+			err = fmt.Errorf("lock '%s' not granted: %d", m.lockName(), status)
+		}
+	case SQLite, SQLiteGo:
+		return nil
+	default:
+		return fmt.Errorf("migrations lock not supported for driver '%s'", m.driver)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to take migrations lock: %w", err)
+	}
+
+	return nil
+}
+
+// unlock uses its own context since the one of the migration may be done, the lock is released anyway
+// when the connection is closed
+func (m *SQLMigrator) unlock(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch m.driver {
+	case Postgres, PostgresPgx:
+		_, _ = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", m.lockKey)
+	case Mysql:
+		_, _ = conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", m.lockName())
+	case SQLServer:
+		_, _ = conn.ExecContext(ctx, "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", m.lockName())
+	}
+}
+
+func (m *SQLMigrator) lockName() string {
+	return fmt.Sprintf("%s_%d", m.table, m.lockKey)
+}
+
+func (m *SQLMigrator) placeholder(i int) string {
+	return LookupSQLDialect(m.driver).Placeholder(i)
+}
@@ -0,0 +1,225 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSQLMigratorMigrations(t *testing.T) {
+	testCases := map[string]struct {
+		files       fstest.MapFS
+		expected    []SQLMigration
+		expectedErr string
+	}{
+		"the migrations should be sorted by version": {
+			files: fstest.MapFS{
+				"migrations/0010_add_email.sql":    {Data: []byte("ALTER TABLE users ADD email TEXT")},
+				"migrations/0002_create_users.sql": {Data: []byte("CREATE TABLE users (id INT)")},
+				"migrations/README.md":             {Data: []byte("docs")},
+			},
+			expected: []SQLMigration{
+				{Version: 2, Name: "create_users", SQL: "CREATE TABLE users (id INT)"},
+				{Version: 10, Name: "add_email", SQL: "ALTER TABLE users ADD email TEXT"},
+			},
+		},
+		"if a file has no version it should fail": {
+			files:       fstest.MapFS{"migrations/users.sql": {}},
+			expectedErr: "migration 'users.sql' must start with its version and an underscore",
+		},
+		"if two files have the same version it should fail": {
+			files: fstest.MapFS{
+				"migrations/1_a.sql":  {},
+				"migrations/01_b.sql": {},
+			},
+			expectedErr: "migrations '01_b.sql' and '1_a.sql' have the same version",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m := NewSQLMigrator(nil, Postgres, tc.files, OptionSQLMigrationsDir("migrations"))
+			migrations, err := m.Migrations()
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, migrations)
+		})
+	}
+}
+
+func TestPendingSQLMigrations(t *testing.T) {
+	migrations := []SQLMigration{{Version: 1}, {Version: 2}, {Version: 3}}
+	applied := []AppliedSQLMigration{{Version: 1}, {Version: 3}}
+
+	assert.Equal(t, []SQLMigration{{Version: 2}}, pendingSQLMigrations(migrations, applied))
+}
+
+func TestSQLMigratorUp(t *testing.T) {
+	const (
+		create   = "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)"
+		selectV  = "SELECT version, name, applied_at FROM schema_migrations ORDER BY version"
+		insertPg = "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)"
+		insertMy = "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"
+		createMs = "IF OBJECT_ID(N'schema_migrations', N'U') IS NULL CREATE TABLE schema_migrations " +
+			"(version BIGINT PRIMARY KEY, name NVARCHAR(255) NOT NULL, applied_at DATETIME2 NOT NULL)"
+		insertMs = "INSERT INTO schema_migrations (version, name, applied_at) VALUES (@p1, @p2, @p3)"
+		getLock  = "SELECT GET_LOCK(?, -1)"
+		appLock  = "DECLARE @status INT; EXEC @status = sp_getapplock @Resource = @p1, " +
+			"@LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = -1; SELECT @status"
+	)
+	files := fstest.MapFS{
+		"1_create_users.sql": {Data: []byte("CREATE TABLE users (id INT)")},
+		"2_add_email.sql":    {Data: []byte("ALTER TABLE users ADD email TEXT")},
+	}
+
+	testCases := map[string]struct {
+		driver             DriverName
+		rows               map[string][][]driver.Value
+		errs               map[string]error
+		expectedErr        bool
+		expectedStatements []string
+	}{
+		"with Postgres every migration should be applied in its own transaction while holding the lock": {
+			driver: Postgres,
+			expectedStatements: []string{
+				"SELECT pg_advisory_lock($1)", create, selectV,
+				"BEGIN", "CREATE TABLE users (id INT)", insertPg, "COMMIT",
+				"BEGIN", "ALTER TABLE users ADD email TEXT", insertPg, "COMMIT",
+				"SELECT pg_advisory_unlock($1)",
+			},
+		},
+		"with MySQL only the migrations not applied should be applied": {
+			driver: Mysql,
+			rows: map[string][][]driver.Value{
+				getLock: {{int64(1)}},
+				selectV: {{int64(1), "create_users", time.Now()}},
+			},
+			expectedStatements: []string{
+				getLock, create, selectV,
+				"BEGIN", "ALTER TABLE users ADD email TEXT", insertMy, "COMMIT",
+				"SELECT RELEASE_LOCK(?)",
+			},
+		},
+		"if the MySQL lock isn't granted nothing should be applied": {
+			driver:             Mysql,
+			rows:               map[string][][]driver.Value{getLock: {{int64(0)}}},
+			expectedErr:        true,
+			expectedStatements: []string{getLock},
+		},
+		"if a migration fails it should be rolled back and the next ones shouldn't be applied": {
+			driver:      Postgres,
+			errs:        map[string]error{"CREATE TABLE users (id INT)": errors.New("syntax error")},
+			expectedErr: true,
+			expectedStatements: []string{
+				"SELECT pg_advisory_lock($1)", create, selectV,
+				"BEGIN", "CREATE TABLE users (id INT)", "ROLLBACK",
+				"SELECT pg_advisory_unlock($1)",
+			},
+		},
+		"with SQL Server the application lock should be held while migrating": {
+			driver: SQLServer,
+			rows: map[string][][]driver.Value{
+				appLock: {{int64(0)}},
+				selectV: {{int64(1), "create_users", time.Now()}},
+			},
+			expectedStatements: []string{
+				appLock, createMs, selectV,
+				"BEGIN", "ALTER TABLE users ADD email TEXT", insertMs, "COMMIT",
+				"EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'",
+			},
+		},
+		"if the SQL Server lock isn't granted nothing should be applied": {
+			driver:             SQLServer,
+			rows:               map[string][][]driver.Value{appLock: {{int64(-999)}}},
+			expectedErr:        true,
+			expectedStatements: []string{appLock},
+		},
+		"with SQLite the migrations should be applied without lock": {
+			driver: SQLite,
+			rows:   map[string][][]driver.Value{selectV: {{int64(1), "create_users", time.Now()}}},
+			expectedStatements: []string{
+				create, selectV,
+				"BEGIN", "ALTER TABLE users ADD email TEXT", insertMy, "COMMIT",
+			},
+		},
+		"if the driver has no lock nothing should be applied": {
+			driver:      Oracle,
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db, err := sql.Open("stub", "")
+			require.NoError(t, err)
+			defer db.Close()
+			stubDriver.reset()
+			defer stubDriver.reset()
+			for query, rows := range tc.rows {
+				stubDriver.setRows(query, rows...)
+			}
+			for query, err := range tc.errs {
+				stubDriver.setErr(query, err)
+			}
+
+			err = NewSQLMigrator(db, tc.driver, files).Up(context.Background())
+
+			assert.Equal(t, tc.expectedErr, err != nil)
+			assert.Equal(t, tc.expectedStatements, stubDriver.statements())
+		})
+	}
+}
+
+func TestSQLMigratorApplied(t *testing.T) {
+	const selectV = "SELECT version, name, applied_at FROM schema_migrations ORDER BY version"
+	appliedAt := time.Date(2020, 6, 1, 10, 0, 0, 123456000, time.UTC)
+
+	testCases := map[string]struct {
+		value       driver.Value
+		expectedErr bool
+	}{
+		"a time should be kept": {
+			value: appliedAt,
+		},
+		"a time as bytes without parseTime in MySQL should be parsed": {
+			value: []byte("2020-06-01 10:00:00.123456"),
+		},
+		"a time as RFC 3339 text should be parsed": {
+			value: "2020-06-01T10:00:00.123456Z",
+		},
+		"a text that isn't a time should fail": {
+			value:       "yesterday",
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db, err := sql.Open("stub", "")
+			require.NoError(t, err)
+			defer db.Close()
+			stubDriver.reset()
+			defer stubDriver.reset()
+			stubDriver.setRows(selectV, []driver.Value{int64(1), "create_users", tc.value})
+
+			applied, err := NewSQLMigrator(db, Mysql, fstest.MapFS{}).Applied(context.Background())
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []AppliedSQLMigration{{Version: 1, Name: "create_users", AppliedAt: appliedAt}}, applied)
+		})
+	}
+}
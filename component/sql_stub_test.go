@@ -0,0 +1,157 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// stubSQLDriver opens connections that record the statements executed and the commits and rollbacks,
+// the queries return the rows set with setRows or no rows
+type stubSQLDriver struct {
+	mu   sync.Mutex
+	log  []string
+	rows map[string][][]driver.Value
+	errs map[string]error
+}
+
+var stubDriver = &stubSQLDriver{}
+
+func init() {
+	sql.Register("stub", stubDriver)
+}
+
+func (d *stubSQLDriver) Open(string) (driver.Conn, error) {
+	return stubSQLConn{d}, nil
+}
+
+func (d *stubSQLDriver) record(stmt string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, stmt)
+}
+
+// setRows sets the rows returned by the query until reset is called
+func (d *stubSQLDriver) setRows(query string, rows ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.rows == nil {
+		d.rows = make(map[string][][]driver.Value)
+	}
+	d.rows[query] = rows
+}
+
+// setErr makes the statement fail with the error until reset is called
+func (d *stubSQLDriver) setErr(query string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.errs == nil {
+		d.errs = make(map[string]error)
+	}
+	d.errs[query] = err
+}
+
+// reset removes the rows, the errors and the statements recorded
+func (d *stubSQLDriver) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = nil
+	d.rows = nil
+	d.errs = nil
+}
+
+func (d *stubSQLDriver) result(query string) ([][]driver.Value, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.rows[query], d.errs[query]
+}
+
+// statements returns the statements recorded since the last call
+func (d *stubSQLDriver) statements() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	log := d.log
+	d.log = nil
+
+	return log
+}
+
+type stubSQLConn struct {
+	d *stubSQLDriver
+}
+
+func (c stubSQLConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c stubSQLConn) Close() error {
+	return nil
+}
+
+func (c stubSQLConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return stubSQLTx(c), nil
+}
+
+func (c stubSQLConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	if _, err := c.d.result(query); err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (c stubSQLConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	rows, err := c.d.result(query)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := 0
+	if len(rows) > 0 {
+		columns = len(rows[0])
+	}
+
+	return &stubSQLRows{rows: rows, columns: columns}, nil
+}
+
+type stubSQLRows struct {
+	rows    [][]driver.Value
+	columns int
+}
+
+func (r *stubSQLRows) Columns() []string {
+	return make([]string, r.columns)
+}
+
+func (r *stubSQLRows) Close() error {
+	return nil
+}
+
+func (r *stubSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+type stubSQLTx stubSQLConn
+
+func (t stubSQLTx) Commit() error {
+	t.d.record("COMMIT")
+	return nil
+}
+
+func (t stubSQLTx) Rollback() error {
+	t.d.record("ROLLBACK")
+	return nil
+}
@@ -0,0 +1,144 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// SQLExecutor is where WithTx runs the function. *sql.DB, *sql.Conn and *SQLCluster begin a new transaction,
+// a *sql.Tx creates a savepoint inside the transaction
+type SQLExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type sqlBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TxOptions are the options of WithTx, with the zero value the transaction uses the default isolation level of
+// the database and it's retried up to 3 times, waiting 50ms before the first retry and doubling it up to 1s
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// Retries is how many times the transaction is retried after a serialization failure or a deadlock,
+	// 0 uses the default and a negative number disables the retries
+	Retries    int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+var savepoints uint64
+
+// WithTx runs fn inside a transaction of db, it's committed if fn succeeds and rolled back if fn fails or panics.
+// When the transaction fails because of a serialization failure or a deadlock fn is run again in a new transaction,
+// so it must not have side effects outside of it. If db is a *sql.Tx, fn runs inside a savepoint that is rolled back
+// if it fails, without retries since the whole transaction has to be retried
+func WithTx(ctx context.Context, db SQLExecutor, opts *TxOptions, fn func(*sql.Tx) error) error {
+	if tx, ok := db.(*sql.Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+	beginner, ok := db.(sqlBeginner)
+	if !ok {
+		return fmt.Errorf("unable to begin transaction in %T", db)
+	}
+
+	o := TxOptions{Retries: 3, Backoff: 50 * time.Millisecond, MaxBackoff: time.Second}
+	if opts != nil {
+		o.Isolation, o.ReadOnly = opts.Isolation, opts.ReadOnly
+		if opts.Retries != 0 {
+			o.Retries = opts.Retries
+		}
+		if opts.Backoff > 0 {
+			o.Backoff = opts.Backoff
+		}
+		if opts.MaxBackoff > 0 {
+			o.MaxBackoff = opts.MaxBackoff
+		}
+	}
+	txOpts := &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}
+
+	return retryWithBackoff(ctx, o.Retries, o.Backoff, o.MaxBackoff, IsSerializationFailure, func() error {
+		return runTx(ctx, beginner, txOpts, fn)
+	})
+}
+
+func runTx(ctx context.Context, db sqlBeginner, opts *sql.TxOptions, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	committed = true
+
+	return nil
+}
+
+func withSavepoint(ctx context.Context, tx *sql.Tx, fn func(*sql.Tx) error) error {
+	name := fmt.Sprintf("sp_%d", atomic.AddUint64(&savepoints, 1))
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("unable to create savepoint: %w", err)
+	}
+
+	released := false
+	defer func() {
+		if !released {
+			// the context may be canceled, the savepoint is discarded anyway with the transaction
+			_, _ = tx.ExecContext(context.Background(), "ROLLBACK TO SAVEPOINT "+name)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("unable to release savepoint: %w", err)
+	}
+	released = true
+
+	return nil
+}
+
+// IsSerializationFailure returns true if the transaction failed because of a serialization failure (40001)
+// or a deadlock (40P01) in Postgres, or a deadlock (1213) in MySQL. The errors of the drivers are checked by
+// their SQLState method, like the ones of pgx and lib/pq, or by their Number field, like the ones of go-sql-driver
+func IsSerializationFailure(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if s, ok := err.(interface{ SQLState() string }); ok {
+			if state := s.SQLState(); state == "40001" || state == "40P01" {
+				return true
+			}
+		}
+
+		v := reflect.ValueOf(err)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Struct {
+			if n := v.FieldByName("Number"); n.IsValid() && n.Kind() == reflect.Uint16 && n.Uint() == 1213 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
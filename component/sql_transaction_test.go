@@ -0,0 +1,124 @@
+package component
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sql state " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+func TestWithTx(t *testing.T) {
+	testCases := map[string]struct {
+		fnErrs             []error
+		opts               *TxOptions
+		expectedErr        error
+		expectedStatements []string
+	}{
+		"if the function succeeds it should commit": {
+			fnErrs:             []error{nil},
+			expectedStatements: []string{"BEGIN", "COMMIT"},
+		},
+		"if the function fails it should roll back": {
+			fnErrs:             []error{fmt.Errorf("test")},
+			expectedErr:        fmt.Errorf("test"),
+			expectedStatements: []string{"BEGIN", "ROLLBACK"},
+		},
+		"if there is a serialization failure it should retry": {
+			fnErrs:             []error{fmt.Errorf("insert: %w", sqlStateError("40001")), nil},
+			expectedStatements: []string{"BEGIN", "ROLLBACK", "BEGIN", "COMMIT"},
+		},
+		"if there is a MySQL deadlock it should retry": {
+			fnErrs:             []error{&mysqlError{Number: 1213}, nil},
+			expectedStatements: []string{"BEGIN", "ROLLBACK", "BEGIN", "COMMIT"},
+		},
+		"if the retries are disabled it should fail": {
+			fnErrs:             []error{sqlStateError("40P01")},
+			opts:               &TxOptions{Retries: -1},
+			expectedErr:        sqlStateError("40P01"),
+			expectedStatements: []string{"BEGIN", "ROLLBACK"},
+		},
+		"if the failures continue it should stop retrying": {
+			fnErrs:             []error{sqlStateError("40001"), sqlStateError("40001")},
+			opts:               &TxOptions{Retries: 1},
+			expectedErr:        sqlStateError("40001"),
+			expectedStatements: []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := newStubDBs(t, 1)[0]
+			stubDriver.statements()
+			if tc.opts == nil {
+				tc.opts = &TxOptions{}
+			}
+			tc.opts.Backoff = time.Millisecond
+
+			calls := 0
+			err := WithTx(context.Background(), db, tc.opts, func(*sql.Tx) error {
+				calls++
+				return tc.fnErrs[calls-1]
+			})
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, len(tc.fnErrs), calls)
+			assert.Equal(t, tc.expectedStatements, stubDriver.statements())
+		})
+	}
+}
+
+func TestWithTxNested(t *testing.T) {
+	db := newStubDBs(t, 1)[0]
+	stubDriver.statements()
+
+	err := WithTx(context.Background(), db, nil, func(tx *sql.Tx) error {
+		assert.NoError(t, WithTx(context.Background(), tx, nil, func(*sql.Tx) error {
+			return nil
+		}))
+		assert.EqualError(t, WithTx(context.Background(), tx, nil, func(*sql.Tx) error {
+			return fmt.Errorf("test")
+		}), "test")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	statements := stubDriver.statements()
+	assert.Len(t, statements, 6)
+	assert.Regexp(t, "^SAVEPOINT sp_", statements[1])
+	assert.Regexp(t, "^RELEASE SAVEPOINT sp_", statements[2])
+	assert.Regexp(t, "^ROLLBACK TO SAVEPOINT sp_", statements[4])
+	assert.Equal(t, []string{"BEGIN", "COMMIT"}, []string{statements[0], statements[5]})
+}
+
+func TestWithTxPanic(t *testing.T) {
+	db := newStubDBs(t, 1)[0]
+	stubDriver.statements()
+
+	assert.Panics(t, func() {
+		_ = WithTx(context.Background(), db, nil, func(*sql.Tx) error {
+			panic("test")
+		})
+	})
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, stubDriver.statements())
+}
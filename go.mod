@@ -1,6 +1,6 @@
 module github.com/kayx-org/freja
 
-go 1.14
+go 1.16
 
 require (
 	github.com/arangodb/go-driver v0.0.0-20200618111046-f3a9751e1cf5
@@ -3,8 +3,9 @@ package freja
 import "github.com/kayx-org/freja/healthcheck"
 
 type Status struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	Name    string                 `json:"name"`
+	Status  string                 `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
 }
 
 type healthCalculate struct {
@@ -28,10 +29,15 @@ func (h *healthCalculate) Calculate() (bool, []Status) {
 			finalStatus = false
 		}
 
-		statuses = append(statuses, Status{
+		status := Status{
 			Name:   hc.Name(),
 			Status: hc.Status().ToString(),
-		})
+		}
+		if dhc, ok := hc.(healthcheck.DetailedHealthChecker); ok {
+			status.Details = dhc.Details()
+		}
+
+		statuses = append(statuses, status)
 	}
 
 	return finalStatus, statuses
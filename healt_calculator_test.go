@@ -56,6 +56,22 @@ func TestHealthCalculator(t *testing.T) {
 	}
 }
 
+func TestHealthCalculatorDetails(t *testing.T) {
+	hc := NewHealthCalculator()
+	hc.Add(&mockHC{name: "foo", status: healthcheck.UP})
+	hc.Add(&mockDetailedHC{
+		mockHC:  mockHC{name: "bar", status: healthcheck.UP},
+		details: map[string]interface{}{"latencyMs": int64(3)},
+	})
+
+	status, summary := hc.Calculate()
+	assert.True(t, status)
+	assert.Equal(t, []Status{
+		{Name: "foo", Status: healthcheck.UP.ToString()},
+		{Name: "bar", Status: healthcheck.UP.ToString(), Details: map[string]interface{}{"latencyMs": int64(3)}},
+	}, summary)
+}
+
 type mockHC struct {
 	name   string
 	status healthcheck.ServiceStatus
@@ -67,3 +83,12 @@ func (m *mockHC) Name() string {
 func (m *mockHC) Status() healthcheck.ServiceStatus {
 	return m.status
 }
+
+type mockDetailedHC struct {
+	mockHC
+	details map[string]interface{}
+}
+
+func (m *mockDetailedHC) Details() map[string]interface{} {
+	return m.details
+}
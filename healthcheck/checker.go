@@ -0,0 +1,34 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTemporallyUnavailable can be wrapped by a check to report the dependency as TemporallyUnavailable
+// instead of DOWN, e.g. fmt.Errorf("latency too high: %w", ErrTemporallyUnavailable)
+var ErrTemporallyUnavailable = errors.New("temporally unavailable")
+
+// defaultCheckTimeout is the time given to the checkers to reach the dependency when Status() is called
+const defaultCheckTimeout = time.Second
+
+// StatusFromError translates the result of a check to a ServiceStatus, any error is considered DOWN
+// unless it wraps ErrTemporallyUnavailable
+func StatusFromError(err error) ServiceStatus {
+	switch {
+	case err == nil:
+		return UP
+	case errors.Is(err, ErrTemporallyUnavailable):
+		return TemporallyUnavailable
+	default:
+		return DOWN
+	}
+}
+
+func statusOf(timeout time.Duration, check func(context.Context) error) ServiceStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return StatusFromError(check(ctx))
+}
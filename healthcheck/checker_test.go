@@ -0,0 +1,124 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusFromError(t *testing.T) {
+	assert.Equal(t, UP, StatusFromError(nil))
+	assert.Equal(t, DOWN, StatusFromError(fmt.Errorf("test")))
+	assert.Equal(t, TemporallyUnavailable, StatusFromError(fmt.Errorf("test: %w", ErrTemporallyUnavailable)))
+}
+
+func TestHTTPChecker(t *testing.T) {
+	testCases := map[string]struct {
+		statusCode     int
+		body           string
+		delay          time.Duration
+		options        []OptionHTTPChecker
+		expectedStatus ServiceStatus
+	}{
+		"if the endpoint returns 200 then it should be up": {
+			statusCode:     http.StatusOK,
+			expectedStatus: UP,
+		},
+		"if the endpoint returns an unexpected status code then it should be down": {
+			statusCode:     http.StatusInternalServerError,
+			expectedStatus: DOWN,
+		},
+		"if the endpoint returns the expected status code then it should be up": {
+			statusCode:     http.StatusNoContent,
+			options:        []OptionHTTPChecker{OptionHTTPExpectedStatus(http.StatusNoContent)},
+			expectedStatus: UP,
+		},
+		"if the body contains the expected string then it should be up": {
+			statusCode:     http.StatusOK,
+			body:           `{"status":"ok"}`,
+			options:        []OptionHTTPChecker{OptionHTTPBodyContains(`"ok"`)},
+			expectedStatus: UP,
+		},
+		"if the expected string is beyond the limit of the body then it should be down": {
+			statusCode:     http.StatusOK,
+			body:           strings.Repeat(" ", maxBodySize) + `"ok"`,
+			options:        []OptionHTTPChecker{OptionHTTPBodyContains(`"ok"`)},
+			expectedStatus: DOWN,
+		},
+		"if the body doesn't contain the expected string then it should be down": {
+			statusCode:     http.StatusOK,
+			body:           `{"status":"ko"}`,
+			options:        []OptionHTTPChecker{OptionHTTPBodyContains(`"ok"`)},
+			expectedStatus: DOWN,
+		},
+		"if the headers are missing then it should be down": {
+			statusCode:     http.StatusOK,
+			expectedStatus: DOWN,
+			options:        []OptionHTTPChecker{OptionHTTPHeader("Authorization", "wrong")},
+		},
+		"if the endpoint is too slow then it should be down": {
+			statusCode:     http.StatusOK,
+			delay:          time.Millisecond * 50,
+			options:        []OptionHTTPChecker{OptionHTTPTimeout(time.Millisecond * 5)},
+			expectedStatus: DOWN,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.delay)
+				if auth := r.Header.Get("Authorization"); auth != "" && auth != "token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewHTTPChecker("foo", srv.URL, tc.options...)
+			assert.Equal(t, tc.expectedStatus, c.Status())
+			assert.Equal(t, "foo", c.Name())
+		})
+	}
+}
+
+func TestTCPChecker(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := lis.Addr().String()
+
+	c := NewTCPChecker("foo", addr, OptionTCPTimeout(time.Millisecond*100))
+	assert.Equal(t, UP, c.Status())
+
+	assert.NoError(t, lis.Close())
+	assert.Equal(t, DOWN, c.Status())
+}
+
+func TestDNSChecker(t *testing.T) {
+	assert.Equal(t, UP, NewDNSChecker("foo", "localhost").Status())
+	assert.Equal(t, DOWN, NewDNSChecker("foo", "foo.invalid", OptionDNSTimeout(time.Millisecond*100)).Status())
+}
+
+func TestResourceCheckers(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resource checks are only supported on linux")
+	}
+
+	assert.Equal(t, UP, NewDiskSpaceChecker("foo", "/", 0).Status())
+	assert.Equal(t, TemporallyUnavailable, NewDiskSpaceChecker("foo", "/", math.MaxUint64).Status())
+	assert.Equal(t, DOWN, NewDiskSpaceChecker("foo", "/does/not/exist", 0).Status())
+
+	assert.Equal(t, UP, NewMemoryChecker("foo", 0).Status())
+	assert.Equal(t, TemporallyUnavailable, NewMemoryChecker("foo", math.MaxUint64).Status())
+	assert.NoError(t, NewMemoryChecker("foo", 0).Check(context.Background()))
+}
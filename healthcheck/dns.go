@@ -0,0 +1,65 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+type OptionDNSChecker func(*DNSChecker)
+
+// DNSChecker checks that the given host name resolves to at least one address
+type DNSChecker struct {
+	name     string
+	host     string
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+func NewDNSChecker(name, host string, options ...OptionDNSChecker) *DNSChecker {
+	c := &DNSChecker{
+		name:     name,
+		host:     host,
+		resolver: net.DefaultResolver,
+		timeout:  defaultCheckTimeout,
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	return c
+}
+
+func OptionDNSTimeout(t time.Duration) OptionDNSChecker {
+	return func(c *DNSChecker) {
+		c.timeout = t
+	}
+}
+
+func OptionDNSResolver(r *net.Resolver) OptionDNSChecker {
+	return func(c *DNSChecker) {
+		c.resolver = r
+	}
+}
+
+func (c *DNSChecker) Name() string {
+	return c.name
+}
+
+func (c *DNSChecker) Status() ServiceStatus {
+	return statusOf(c.timeout, c.Check)
+}
+
+func (c *DNSChecker) Check(ctx context.Context) error {
+	addrs, err := c.resolver.LookupHost(ctx, c.host)
+	if err != nil {
+		return fmt.Errorf("unable to resolve '%s': %w", c.host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("no addresses found for '%s'", c.host)
+	}
+
+	return nil
+}
@@ -20,3 +20,10 @@ type HealthChecker interface {
 	Name() string
 	Status() ServiceStatus
 }
+
+// DetailedHealthChecker is a HealthChecker that is able to report extra information about the
+// dependency it monitors (latency, pool stats, etc), the details will be added to the health-check summary
+type DetailedHealthChecker interface {
+	HealthChecker
+	Details() map[string]interface{}
+}
@@ -0,0 +1,117 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize is how much of the body is read to look for the expected string
+const maxBodySize = 64 << 10
+
+type OptionHTTPChecker func(*HTTPChecker)
+
+// HTTPChecker checks that an HTTP endpoint answers with the expected status code and, optionally,
+// that the body contains a given string. The check runs every time Status() is called, if the endpoint
+// is slow consider running Check through middleware.NewPoller instead
+type HTTPChecker struct {
+	name           string
+	url            string
+	client         *http.Client
+	timeout        time.Duration
+	headers        http.Header
+	expectedStatus int
+	bodyContains   string
+}
+
+func NewHTTPChecker(name, url string, options ...OptionHTTPChecker) *HTTPChecker {
+	c := &HTTPChecker{
+		name:           name,
+		url:            url,
+		client:         http.DefaultClient,
+		timeout:        defaultCheckTimeout,
+		headers:        http.Header{},
+		expectedStatus: http.StatusOK,
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	return c
+}
+
+func OptionHTTPTimeout(t time.Duration) OptionHTTPChecker {
+	return func(c *HTTPChecker) {
+		c.timeout = t
+	}
+}
+
+func OptionHTTPClient(client *http.Client) OptionHTTPChecker {
+	return func(c *HTTPChecker) {
+		c.client = client
+	}
+}
+
+func OptionHTTPHeader(key, value string) OptionHTTPChecker {
+	return func(c *HTTPChecker) {
+		c.headers.Add(key, value)
+	}
+}
+
+func OptionHTTPExpectedStatus(code int) OptionHTTPChecker {
+	return func(c *HTTPChecker) {
+		c.expectedStatus = code
+	}
+}
+
+// OptionHTTPBodyContains sets a string that must be in the first 64KB of the body
+func OptionHTTPBodyContains(s string) OptionHTTPChecker {
+	return func(c *HTTPChecker) {
+		c.bodyContains = s
+	}
+}
+
+func (c *HTTPChecker) Name() string {
+	return c.name
+}
+
+func (c *HTTPChecker) Status() ServiceStatus {
+	return statusOf(c.timeout, c.Check)
+}
+
+func (c *HTTPChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	for k, v := range c.headers {
+		req.Header[k] = v
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach '%s': %w", c.url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != c.expectedStatus {
+		return fmt.Errorf("unexpected status code %d, expected %d", res.StatusCode, c.expectedStatus)
+	}
+
+	if c.bodyContains != "" {
+		body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxBodySize))
+		if err != nil {
+			return fmt.Errorf("unable to read body: %w", err)
+		}
+		if !strings.Contains(string(body), c.bodyContains) {
+			return fmt.Errorf("body does not contain '%s'", c.bodyContains)
+		}
+	}
+
+	return nil
+}
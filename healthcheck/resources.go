@@ -0,0 +1,76 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+)
+
+// DiskSpaceChecker checks that the file system holding the given path has at least minFree bytes available,
+// below that the status is TemporallyUnavailable
+type DiskSpaceChecker struct {
+	name    string
+	path    string
+	minFree uint64
+}
+
+func NewDiskSpaceChecker(name, path string, minFree uint64) *DiskSpaceChecker {
+	return &DiskSpaceChecker{
+		name:    name,
+		path:    path,
+		minFree: minFree,
+	}
+}
+
+func (c *DiskSpaceChecker) Name() string {
+	return c.name
+}
+
+func (c *DiskSpaceChecker) Status() ServiceStatus {
+	return StatusFromError(c.Check(context.Background()))
+}
+
+func (c *DiskSpaceChecker) Check(context.Context) error {
+	free, err := freeDiskSpace(c.path)
+	if err != nil {
+		return fmt.Errorf("unable to get the disk space of '%s': %w", c.path, err)
+	}
+	if free < c.minFree {
+		return fmt.Errorf("%d bytes free in '%s', below %d: %w", free, c.path, c.minFree, ErrTemporallyUnavailable)
+	}
+
+	return nil
+}
+
+// MemoryChecker checks that the system has at least minFree bytes of memory available,
+// below that the status is TemporallyUnavailable
+type MemoryChecker struct {
+	name    string
+	minFree uint64
+}
+
+func NewMemoryChecker(name string, minFree uint64) *MemoryChecker {
+	return &MemoryChecker{
+		name:    name,
+		minFree: minFree,
+	}
+}
+
+func (c *MemoryChecker) Name() string {
+	return c.name
+}
+
+func (c *MemoryChecker) Status() ServiceStatus {
+	return StatusFromError(c.Check(context.Background()))
+}
+
+func (c *MemoryChecker) Check(context.Context) error {
+	free, err := freeMemory()
+	if err != nil {
+		return fmt.Errorf("unable to get the free memory: %w", err)
+	}
+	if free < c.minFree {
+		return fmt.Errorf("%d bytes of memory free, below %d: %w", free, c.minFree, ErrTemporallyUnavailable)
+	}
+
+	return nil
+}
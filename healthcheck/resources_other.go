@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package healthcheck
+
+import "errors"
+
+var errResourcesNotSupported = errors.New("resource checks are only supported on linux")
+
+func freeDiskSpace(string) (uint64, error) {
+	return 0, errResourcesNotSupported
+}
+
+func freeMemory() (uint64, error) {
+	return 0, errResourcesNotSupported
+}
@@ -0,0 +1,55 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+type OptionTCPChecker func(*TCPChecker)
+
+// TCPChecker checks that a TCP connection can be established with the given address
+type TCPChecker struct {
+	name    string
+	addr    string
+	timeout time.Duration
+}
+
+func NewTCPChecker(name, addr string, options ...OptionTCPChecker) *TCPChecker {
+	c := &TCPChecker{
+		name:    name,
+		addr:    addr,
+		timeout: defaultCheckTimeout,
+	}
+
+	for _, o := range options {
+		o(c)
+	}
+
+	return c
+}
+
+func OptionTCPTimeout(t time.Duration) OptionTCPChecker {
+	return func(c *TCPChecker) {
+		c.timeout = t
+	}
+}
+
+func (c *TCPChecker) Name() string {
+	return c.name
+}
+
+func (c *TCPChecker) Status() ServiceStatus {
+	return statusOf(c.timeout, c.Check)
+}
+
+func (c *TCPChecker) Check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("unable to dial '%s': %w", c.addr, err)
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"github.com/kayx-org/freja/healthcheck"
+	"time"
+)
+
+//go:generate moq -out arango_mock_test.go . arangoDB
+type arangoDB interface {
+	InitDB(ctx context.Context) error
+	Version(ctx context.Context) (driver.VersionInfo, error)
+	ClusterHealth(ctx context.Context) (driver.ClusterHealth, error)
+	Endpoints() []string
+	PingEndpoints(ctx context.Context) map[string]error
+	Close() error
+}
+
+type OptionArangoMiddleware func(*arangoMiddleware)
+
+type arangoMiddleware struct {
+	*Poller
+	arango      arangoDB
+	initTimeout time.Duration
+}
+
+// NewArangoMiddleware returns a new middleware that initialises the ArangoDB connection, checks the server version,
+// the cluster health and, if several endpoints are configured, every coordinator on its own
+func NewArangoMiddleware(arango arangoDB, options ...OptionArangoMiddleware) *arangoMiddleware {
+	m := &arangoMiddleware{
+		arango:      arango,
+		initTimeout: time.Second * 10,
+	}
+	m.Poller = NewPoller("arangodb", m.check)
+
+	for _, op := range options {
+		op(m)
+	}
+
+	return m
+}
+
+func OptionArangoWindowCheck(t time.Duration) OptionArangoMiddleware {
+	return func(m *arangoMiddleware) {
+		m.interval = t
+	}
+}
+
+func OptionArangoHealthCheckName(name string) OptionArangoMiddleware {
+	return func(m *arangoMiddleware) {
+		m.name = name
+	}
+}
+
+// OptionArangoThresholds sets the number of consecutive failed checks before the status goes DOWN
+// and the number of consecutive successful ones before it goes UP again
+func OptionArangoThresholds(failures, successes int) OptionArangoMiddleware {
+	return func(m *arangoMiddleware) {
+		m.status.setThresholds(failures, successes)
+	}
+}
+
+// OptionArangoInitTimeout sets the time given to InitDB to connect and create the database
+func OptionArangoInitTimeout(t time.Duration) OptionArangoMiddleware {
+	return func(m *arangoMiddleware) {
+		m.initTimeout = t
+	}
+}
+
+func (m *arangoMiddleware) Init() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.initTimeout)
+	defer cancel()
+
+	if err := m.arango.InitDB(ctx); err != nil {
+		return fmt.Errorf("unable to init arangodb: %w", err)
+	}
+
+	return nil
+}
+
+func (m *arangoMiddleware) Stop(context.Context) error {
+	return m.arango.Close()
+}
+
+func (m *arangoMiddleware) check(ctx context.Context) error {
+	details := map[string]interface{}{}
+	defer func() {
+		m.status.setDetails(details)
+	}()
+
+	version, err := m.arango.Version(ctx)
+	if err != nil {
+		details["error"] = err.Error()
+		return err
+	}
+	details["version"] = string(version.Version)
+	details["license"] = version.License
+
+	var degraded []string
+	if len(m.arango.Endpoints()) > 1 {
+		endpoints := map[string]string{}
+		for ep, err := range m.arango.PingEndpoints(ctx) {
+			endpoints[ep] = healthcheck.UP.ToString()
+			if err != nil {
+				endpoints[ep] = healthcheck.DOWN.ToString()
+				degraded = append(degraded, ep)
+			}
+		}
+		details["endpoints"] = endpoints
+	}
+
+	health, err := m.arango.ClusterHealth(ctx)
+	switch {
+	case driver.IsPreconditionFailed(err):
+		// it's a single server, there is no cluster health to check
+	case err != nil:
+		details["cluster"] = err.Error()
+		degraded = append(degraded, "cluster")
+	default:
+		servers := map[string]string{}
+		for id, s := range health.Health {
+			servers[string(id)] = string(s.Status)
+			if s.Status != driver.ServerStatusGood {
+				degraded = append(degraded, string(id))
+			}
+		}
+		details["cluster"] = servers
+	}
+
+	if len(degraded) > 0 {
+		return fmt.Errorf("unhealthy servers %v: %w", degraded, healthcheck.ErrTemporallyUnavailable)
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package middleware
+
+import (
+	"context"
+	"github.com/arangodb/go-driver"
+	"sync"
+)
+
+var (
+	lockarangoDBMockClose         sync.RWMutex
+	lockarangoDBMockClusterHealth sync.RWMutex
+	lockarangoDBMockEndpoints     sync.RWMutex
+	lockarangoDBMockInitDB        sync.RWMutex
+	lockarangoDBMockPingEndpoints sync.RWMutex
+	lockarangoDBMockVersion       sync.RWMutex
+)
+
+// Ensure, that arangoDBMock does implement arangoDB.
+// If this is not the case, regenerate this file with moq.
+var _ arangoDB = &arangoDBMock{}
+
+// arangoDBMock is a mock implementation of arangoDB.
+//
+//     func TestSomethingThatUsesarangoDB(t *testing.T) {
+//
+//         // make and configure a mocked arangoDB
+//         mockedarangoDB := &arangoDBMock{
+//             CloseFunc: func() error {
+// 	               panic("mock out the Close method")
+//             },
+//             ClusterHealthFunc: func(ctx context.Context) (driver.ClusterHealth, error) {
+// 	               panic("mock out the ClusterHealth method")
+//             },
+//             EndpointsFunc: func() []string {
+// 	               panic("mock out the Endpoints method")
+//             },
+//             InitDBFunc: func(ctx context.Context) error {
+// 	               panic("mock out the InitDB method")
+//             },
+//             PingEndpointsFunc: func(ctx context.Context) map[string]error {
+// 	               panic("mock out the PingEndpoints method")
+//             },
+//             VersionFunc: func(ctx context.Context) (driver.VersionInfo, error) {
+// 	               panic("mock out the Version method")
+//             },
+//         }
+//
+//         // use mockedarangoDB in code that requires arangoDB
+//         // and then make assertions.
+//
+//     }
+type arangoDBMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// ClusterHealthFunc mocks the ClusterHealth method.
+	ClusterHealthFunc func(ctx context.Context) (driver.ClusterHealth, error)
+
+	// EndpointsFunc mocks the Endpoints method.
+	EndpointsFunc func() []string
+
+	// InitDBFunc mocks the InitDB method.
+	InitDBFunc func(ctx context.Context) error
+
+	// PingEndpointsFunc mocks the PingEndpoints method.
+	PingEndpointsFunc func(ctx context.Context) map[string]error
+
+	// VersionFunc mocks the Version method.
+	VersionFunc func(ctx context.Context) (driver.VersionInfo, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// ClusterHealth holds details about calls to the ClusterHealth method.
+		ClusterHealth []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Endpoints holds details about calls to the Endpoints method.
+		Endpoints []struct {
+		}
+		// InitDB holds details about calls to the InitDB method.
+		InitDB []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// PingEndpoints holds details about calls to the PingEndpoints method.
+		PingEndpoints []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Version holds details about calls to the Version method.
+		Version []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+}
+
+// Close calls CloseFunc.
+func (mock *arangoDBMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("arangoDBMock.CloseFunc: method is nil but arangoDB.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockarangoDBMockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	lockarangoDBMockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//     len(mockedarangoDB.CloseCalls())
+func (mock *arangoDBMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockarangoDBMockClose.RLock()
+	calls = mock.calls.Close
+	lockarangoDBMockClose.RUnlock()
+	return calls
+}
+
+// ClusterHealth calls ClusterHealthFunc.
+func (mock *arangoDBMock) ClusterHealth(ctx context.Context) (driver.ClusterHealth, error) {
+	if mock.ClusterHealthFunc == nil {
+		panic("arangoDBMock.ClusterHealthFunc: method is nil but arangoDB.ClusterHealth was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockarangoDBMockClusterHealth.Lock()
+	mock.calls.ClusterHealth = append(mock.calls.ClusterHealth, callInfo)
+	lockarangoDBMockClusterHealth.Unlock()
+	return mock.ClusterHealthFunc(ctx)
+}
+
+// ClusterHealthCalls gets all the calls that were made to ClusterHealth.
+// Check the length with:
+//     len(mockedarangoDB.ClusterHealthCalls())
+func (mock *arangoDBMock) ClusterHealthCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockarangoDBMockClusterHealth.RLock()
+	calls = mock.calls.ClusterHealth
+	lockarangoDBMockClusterHealth.RUnlock()
+	return calls
+}
+
+// Endpoints calls EndpointsFunc.
+func (mock *arangoDBMock) Endpoints() []string {
+	if mock.EndpointsFunc == nil {
+		panic("arangoDBMock.EndpointsFunc: method is nil but arangoDB.Endpoints was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockarangoDBMockEndpoints.Lock()
+	mock.calls.Endpoints = append(mock.calls.Endpoints, callInfo)
+	lockarangoDBMockEndpoints.Unlock()
+	return mock.EndpointsFunc()
+}
+
+// EndpointsCalls gets all the calls that were made to Endpoints.
+// Check the length with:
+//     len(mockedarangoDB.EndpointsCalls())
+func (mock *arangoDBMock) EndpointsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockarangoDBMockEndpoints.RLock()
+	calls = mock.calls.Endpoints
+	lockarangoDBMockEndpoints.RUnlock()
+	return calls
+}
+
+// InitDB calls InitDBFunc.
+func (mock *arangoDBMock) InitDB(ctx context.Context) error {
+	if mock.InitDBFunc == nil {
+		panic("arangoDBMock.InitDBFunc: method is nil but arangoDB.InitDB was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockarangoDBMockInitDB.Lock()
+	mock.calls.InitDB = append(mock.calls.InitDB, callInfo)
+	lockarangoDBMockInitDB.Unlock()
+	return mock.InitDBFunc(ctx)
+}
+
+// InitDBCalls gets all the calls that were made to InitDB.
+// Check the length with:
+//     len(mockedarangoDB.InitDBCalls())
+func (mock *arangoDBMock) InitDBCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockarangoDBMockInitDB.RLock()
+	calls = mock.calls.InitDB
+	lockarangoDBMockInitDB.RUnlock()
+	return calls
+}
+
+// PingEndpoints calls PingEndpointsFunc.
+func (mock *arangoDBMock) PingEndpoints(ctx context.Context) map[string]error {
+	if mock.PingEndpointsFunc == nil {
+		panic("arangoDBMock.PingEndpointsFunc: method is nil but arangoDB.PingEndpoints was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockarangoDBMockPingEndpoints.Lock()
+	mock.calls.PingEndpoints = append(mock.calls.PingEndpoints, callInfo)
+	lockarangoDBMockPingEndpoints.Unlock()
+	return mock.PingEndpointsFunc(ctx)
+}
+
+// PingEndpointsCalls gets all the calls that were made to PingEndpoints.
+// Check the length with:
+//     len(mockedarangoDB.PingEndpointsCalls())
+func (mock *arangoDBMock) PingEndpointsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockarangoDBMockPingEndpoints.RLock()
+	calls = mock.calls.PingEndpoints
+	lockarangoDBMockPingEndpoints.RUnlock()
+	return calls
+}
+
+// Version calls VersionFunc.
+func (mock *arangoDBMock) Version(ctx context.Context) (driver.VersionInfo, error) {
+	if mock.VersionFunc == nil {
+		panic("arangoDBMock.VersionFunc: method is nil but arangoDB.Version was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockarangoDBMockVersion.Lock()
+	mock.calls.Version = append(mock.calls.Version, callInfo)
+	lockarangoDBMockVersion.Unlock()
+	return mock.VersionFunc(ctx)
+}
+
+// VersionCalls gets all the calls that were made to Version.
+// Check the length with:
+//     len(mockedarangoDB.VersionCalls())
+func (mock *arangoDBMock) VersionCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockarangoDBMockVersion.RLock()
+	calls = mock.calls.Version
+	lockarangoDBMockVersion.RUnlock()
+	return calls
+}
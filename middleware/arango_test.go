@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/arangodb/go-driver"
+	"github.com/kayx-org/freja/component"
+	"github.com/kayx-org/freja/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var _ arangoDB = &component.Arango{}
+
+func TestArangoMiddlewareStatusCheck(t *testing.T) {
+	singleServer := driver.ArangoError{HasError: true, Code: 412, ErrorMessage: "Cluster expected, found SINGLE server"}
+	testCases := map[string]struct {
+		versionErr       error
+		endpoints        []string
+		endpointErrs     map[string]error
+		clusterHealth    driver.ClusterHealth
+		clusterErr       error
+		expectedStatus   healthcheck.ServiceStatus
+		expectedPingEPs  int
+		expectedEPStatus map[string]string
+	}{
+		"if the version can't be fetched then it should be down": {
+			versionErr:     fmt.Errorf("test"),
+			endpoints:      []string{"a"},
+			clusterErr:     singleServer,
+			expectedStatus: healthcheck.DOWN,
+		},
+		"if it's a single server and the version is fetched then it should be up": {
+			endpoints:      []string{"a"},
+			clusterErr:     singleServer,
+			expectedStatus: healthcheck.UP,
+		},
+		"if one of the coordinators is down then it should be temporally unavailable": {
+			endpoints:        []string{"a", "b"},
+			endpointErrs:     map[string]error{"a": nil, "b": fmt.Errorf("test")},
+			clusterErr:       singleServer,
+			expectedStatus:   healthcheck.TemporallyUnavailable,
+			expectedPingEPs:  1,
+			expectedEPStatus: map[string]string{"a": "up", "b": "down"},
+		},
+		"if all the servers in the cluster are good then it should be up": {
+			endpoints:    []string{"a", "b"},
+			endpointErrs: map[string]error{"a": nil, "b": nil},
+			clusterHealth: driver.ClusterHealth{Health: map[driver.ServerID]driver.ServerHealth{
+				"CRDN-1": {Status: driver.ServerStatusGood},
+				"PRMR-1": {Status: driver.ServerStatusGood},
+			}},
+			expectedStatus:   healthcheck.UP,
+			expectedPingEPs:  1,
+			expectedEPStatus: map[string]string{"a": "up", "b": "up"},
+		},
+		"if a server in the cluster has failed then it should be temporally unavailable": {
+			endpoints:    []string{"a", "b"},
+			endpointErrs: map[string]error{"a": nil, "b": nil},
+			clusterHealth: driver.ClusterHealth{Health: map[driver.ServerID]driver.ServerHealth{
+				"CRDN-1": {Status: driver.ServerStatusGood},
+				"PRMR-1": {Status: driver.ServerStatusFailed},
+			}},
+			expectedStatus:   healthcheck.TemporallyUnavailable,
+			expectedPingEPs:  1,
+			expectedEPStatus: map[string]string{"a": "up", "b": "up"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			arango := &arangoDBMock{
+				VersionFunc: func(context.Context) (driver.VersionInfo, error) {
+					return driver.VersionInfo{Version: "3.6.4"}, tc.versionErr
+				},
+				EndpointsFunc: func() []string {
+					return tc.endpoints
+				},
+				PingEndpointsFunc: func(context.Context) map[string]error {
+					return tc.endpointErrs
+				},
+				ClusterHealthFunc: func(context.Context) (driver.ClusterHealth, error) {
+					return tc.clusterHealth, tc.clusterErr
+				},
+			}
+			midd := NewArangoMiddleware(arango)
+			midd.runStatusCheck(context.Background())
+
+			assert.Equal(t, tc.expectedStatus, midd.Status())
+			assert.Len(t, arango.PingEndpointsCalls(), tc.expectedPingEPs)
+			if tc.expectedEPStatus != nil {
+				assert.Equal(t, tc.expectedEPStatus, midd.Details()["endpoints"])
+			}
+		})
+	}
+}
+
+func TestArangoMiddlewareLifecycle(t *testing.T) {
+	arango := &arangoDBMock{
+		InitDBFunc: func(context.Context) error {
+			return fmt.Errorf("test")
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+	midd := NewArangoMiddleware(arango)
+
+	assert.EqualError(t, midd.Init(), "unable to init arangodb: test")
+	assert.NoError(t, midd.Stop(context.Background()))
+	assert.Len(t, arango.InitDBCalls(), 1)
+	assert.Len(t, arango.CloseCalls(), 1)
+}
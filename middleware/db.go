@@ -2,7 +2,10 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"github.com/kayx-org/freja/healthcheck"
+	"sync"
 	"time"
 )
 
@@ -10,25 +13,31 @@ import (
 type db interface {
 	Close() error
 	PingContext(context.Context) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Stats() sql.DBStats
 }
 
+const (
+	minStartupBackoff = 100 * time.Millisecond
+	maxStartupBackoff = 5 * time.Second
+)
+
 type OptionDbMiddleware func(*dbMiddleware)
 
 type dbMiddleware struct {
-	db          db
-	name        string
-	checkWindow time.Duration
-	status      healthcheck.ServiceStatus
+	*Poller
+	db            db
+	maxWaitGrowth time.Duration
+	healthQuery   string
+	optional      bool
+	mu            sync.RWMutex
+	stats         *sql.DBStats
 }
 
 // NewDB returns a new DB middleware which also implements the HealthCheck interface and can be configured accordinginly
 func NewDB(db db, options ...OptionDbMiddleware) *dbMiddleware {
-	midDb := &dbMiddleware{
-		db:          db,
-		name:        "db",
-		checkWindow: time.Second,
-		status:      healthcheck.UP,
-	}
+	midDb := &dbMiddleware{db: db}
+	midDb.Poller = NewPoller("db", midDb.ping)
 
 	for _, op := range options {
 		op(midDb)
@@ -39,7 +48,7 @@ func NewDB(db db, options ...OptionDbMiddleware) *dbMiddleware {
 
 func OptionWindowCheck(t time.Duration) OptionDbMiddleware {
 	return func(m *dbMiddleware) {
-		m.checkWindow = t
+		m.interval = t
 	}
 }
 
@@ -49,18 +58,37 @@ func OptionHealthCheckName(t string) OptionDbMiddleware {
 	}
 }
 
-func (m *dbMiddleware) Init() error {
-	return nil
+// OptionThresholds sets the number of consecutive failed checks before the status goes DOWN
+// and the number of consecutive successful ones before it goes UP again
+func OptionThresholds(failures, successes int) OptionDbMiddleware {
+	return func(m *dbMiddleware) {
+		m.status.setThresholds(failures, successes)
+	}
 }
 
-func (m *dbMiddleware) Run(ctx context.Context) error {
-	ticker := time.NewTicker(m.checkWindow)
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			m.runStatusCheck(ctx)
+// OptionMaxWaitGrowth sets how much the time waited for a new connection can grow between two checks,
+// above that the pool is considered saturated and the status becomes TemporallyUnavailable
+func OptionMaxWaitGrowth(t time.Duration) OptionDbMiddleware {
+	return func(m *dbMiddleware) {
+		m.maxWaitGrowth = t
+	}
+}
+
+// OptionHealthQuery sets a query run by the checks after the ping, like the HealthQuery of the dialect of the
+// driver returned by component.LookupSQLDialect
+func OptionHealthQuery(query string) OptionDbMiddleware {
+	return func(m *dbMiddleware) {
+		m.healthQuery = query
+	}
+}
+
+// OptionStartupPing makes Init ping the database until it succeeds or the timeout expires, waiting the backoff
+// between the attempts and doubling it up to 5 seconds, so the service doesn't start until the database is reachable.
+// The backoff is at least 100 milliseconds
+func OptionStartupPing(timeout, backoff time.Duration) OptionDbMiddleware {
+	return func(m *dbMiddleware) {
+		m.init = func() error {
+			return m.startupPing(timeout, backoff)
 		}
 	}
 }
@@ -69,21 +97,89 @@ func (m *dbMiddleware) Stop(context.Context) error {
 	return m.db.Close()
 }
 
-func (m *dbMiddleware) Name() string {
-	return m.name
+// Status returns the status of the last checks, the optional databases, like the replicas of a cluster,
+// are TemporallyUnavailable instead of DOWN so they don't make the service unhealthy. The notify function
+// of the poller still gets the real status
+func (m *dbMiddleware) Status() healthcheck.ServiceStatus {
+	status := m.Poller.Status()
+	if m.optional && status.IsDown() {
+		return healthcheck.TemporallyUnavailable
+	}
+
+	return status
 }
 
-func (m *dbMiddleware) runStatusCheck(ctx context.Context) {
-	ctx, cancel := context.WithTimeout(ctx, m.checkWindow)
+// Stats returns the pool stats taken in the last check, so they can be exported as metrics
+func (m *dbMiddleware) Stats() sql.DBStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.stats == nil {
+		return sql.DBStats{}
+	}
+
+	return *m.stats
+}
+
+func (m *dbMiddleware) startupPing(timeout, backoff time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	if err := m.db.PingContext(ctx); err != nil {
-		m.status = healthcheck.DOWN
-	} else {
-		m.status = healthcheck.UP
+	if backoff < minStartupBackoff {
+		backoff = minStartupBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := m.db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to reach the database after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxStartupBackoff {
+			backoff = maxStartupBackoff
+		}
 	}
 }
 
-func (m *dbMiddleware) Status() healthcheck.ServiceStatus {
-	return m.status
+func (m *dbMiddleware) ping(ctx context.Context) error {
+	err := m.db.PingContext(ctx)
+	if err == nil && m.healthQuery != "" {
+		if _, qErr := m.db.ExecContext(ctx, m.healthQuery); qErr != nil {
+			err = fmt.Errorf("health query failed: %w", qErr)
+		}
+	}
+
+	stats := m.db.Stats()
+	var waitGrowth time.Duration
+	m.mu.Lock()
+	if m.stats != nil {
+		waitGrowth = stats.WaitDuration - m.stats.WaitDuration
+	}
+	m.stats = &stats
+	m.mu.Unlock()
+
+	m.status.setDetails(map[string]interface{}{
+		"maxOpenConnections": stats.MaxOpenConnections,
+		"openConnections":    stats.OpenConnections,
+		"inUse":              stats.InUse,
+		"idle":               stats.Idle,
+		"waitCount":          stats.WaitCount,
+		"waitDurationMs":     stats.WaitDuration.Milliseconds(),
+		"maxIdleClosed":      stats.MaxIdleClosed,
+		"maxLifetimeClosed":  stats.MaxLifetimeClosed,
+		"maxIdleTimeClosed":  stats.MaxIdleTimeClosed,
+	})
+
+	switch {
+	case err != nil:
+		return err
+	case m.maxWaitGrowth > 0 && waitGrowth > m.maxWaitGrowth:
+		return fmt.Errorf("wait duration grew %s, above %s: %w", waitGrowth, m.maxWaitGrowth, healthcheck.ErrTemporallyUnavailable)
+	default:
+		return nil
+	}
 }
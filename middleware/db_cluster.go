@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/kayx-org/freja/healthcheck"
+)
+
+type dbCluster interface {
+	Writer() *sql.DB
+	Readers() []*sql.DB
+	SetReplicaHealthy(i int, healthy bool)
+}
+
+// NewDBCluster returns a DB middleware for every pool of a component.SQLCluster, so each one is reported
+// separately as <name>-primary and <name>-replica-<i>. The status of the replicas is reported to the cluster,
+// the ones DOWN stop receiving reads until they are UP again. Since the reads go to the primary when the
+// replicas are down, a replica DOWN is reported as TemporallyUnavailable so the service stays healthy
+func NewDBCluster(cluster dbCluster, options ...OptionDbMiddleware) []*dbMiddleware {
+	writer := NewDB(cluster.Writer(), options...)
+	writer.name += "-primary"
+	mids := []*dbMiddleware{writer}
+
+	for i, r := range cluster.Readers() {
+		i := i
+		reader := NewDB(r, options...)
+		reader.name = fmt.Sprintf("%s-replica-%d", reader.name, i)
+		reader.optional = true
+		reader.notify = func(status healthcheck.ServiceStatus) {
+			cluster.SetReplicaHealthy(i, !status.IsDown())
+		}
+		mids = append(mids, reader)
+	}
+
+	return mids
+}
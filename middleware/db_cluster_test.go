@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/kayx-org/freja"
+	"github.com/kayx-org/freja/component"
+	"github.com/kayx-org/freja/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// stubDriver fails to connect to the DSN "down"
+type stubDriver struct{}
+
+func (stubDriver) Open(name string) (driver.Conn, error) {
+	if name == "down" {
+		return nil, errors.New("connection refused")
+	}
+
+	return stubConn{}, nil
+}
+
+type stubConn struct{}
+
+func (stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (stubConn) Close() error {
+	return nil
+}
+
+func (stubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func init() {
+	sql.Register("stub", stubDriver{})
+}
+
+func TestNewDBCluster(t *testing.T) {
+	var dbs []*sql.DB
+	for _, dsn := range []string{"up", "down", "up"} {
+		db, err := sql.Open("stub", dsn)
+		assert.NoError(t, err)
+		defer db.Close()
+		dbs = append(dbs, db)
+	}
+	cluster := component.NewSQLCluster(dbs[0], dbs[1:])
+
+	mids := NewDBCluster(cluster, OptionHealthCheckName("users"), OptionWindowCheck(time.Second))
+	for _, m := range mids {
+		m.runStatusCheck(context.Background())
+	}
+
+	var names []string
+	var statuses []healthcheck.ServiceStatus
+	for _, m := range mids {
+		names = append(names, m.Name())
+		statuses = append(statuses, m.Status())
+	}
+	assert.Equal(t, []string{"users-primary", "users-replica-0", "users-replica-1"}, names)
+	assert.Equal(t, []healthcheck.ServiceStatus{healthcheck.UP, healthcheck.TemporallyUnavailable, healthcheck.UP}, statuses)
+	for i := 0; i < 3; i++ {
+		assert.Same(t, dbs[2], cluster.Reader())
+	}
+
+	health := freja.NewHealthCalculator()
+	for _, m := range mids {
+		health.Add(m)
+	}
+	healthy, _ := health.Calculate()
+	assert.True(t, healthy)
+}
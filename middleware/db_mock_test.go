@@ -5,12 +5,15 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
 	"sync"
 )
 
 var (
 	lockdbMockClose       sync.RWMutex
+	lockdbMockExecContext sync.RWMutex
 	lockdbMockPingContext sync.RWMutex
+	lockdbMockStats       sync.RWMutex
 )
 
 // Ensure, that dbMock does implement db.
@@ -26,9 +29,15 @@ var _ db = &dbMock{}
 //             CloseFunc: func() error {
 // 	               panic("mock out the Close method")
 //             },
+//             ExecContextFunc: func(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+// 	               panic("mock out the ExecContext method")
+//             },
 //             PingContextFunc: func(in1 context.Context) error {
 // 	               panic("mock out the PingContext method")
 //             },
+//             StatsFunc: func() sql.DBStats {
+// 	               panic("mock out the Stats method")
+//             },
 //         }
 //
 //         // use mockeddb in code that requires db
@@ -39,19 +48,37 @@ type dbMock struct {
 	// CloseFunc mocks the Close method.
 	CloseFunc func() error
 
+	// ExecContextFunc mocks the ExecContext method.
+	ExecContextFunc func(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+
 	// PingContextFunc mocks the PingContext method.
 	PingContextFunc func(in1 context.Context) error
 
+	// StatsFunc mocks the Stats method.
+	StatsFunc func() sql.DBStats
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Close holds details about calls to the Close method.
 		Close []struct {
 		}
+		// ExecContext holds details about calls to the ExecContext method.
+		ExecContext []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query string
+			// Args is the args argument value.
+			Args []interface{}
+		}
 		// PingContext holds details about calls to the PingContext method.
 		PingContext []struct {
 			// In1 is the in1 argument value.
 			In1 context.Context
 		}
+		// Stats holds details about calls to the Stats method.
+		Stats []struct {
+		}
 	}
 }
 
@@ -81,6 +108,45 @@ func (mock *dbMock) CloseCalls() []struct {
 	return calls
 }
 
+// ExecContext calls ExecContextFunc.
+func (mock *dbMock) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if mock.ExecContextFunc == nil {
+		panic("dbMock.ExecContextFunc: method is nil but db.ExecContext was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query string
+		Args  []interface{}
+	}{
+		Ctx:   ctx,
+		Query: query,
+		Args:  args,
+	}
+	lockdbMockExecContext.Lock()
+	mock.calls.ExecContext = append(mock.calls.ExecContext, callInfo)
+	lockdbMockExecContext.Unlock()
+	return mock.ExecContextFunc(ctx, query, args...)
+}
+
+// ExecContextCalls gets all the calls that were made to ExecContext.
+// Check the length with:
+//     len(mockeddb.ExecContextCalls())
+func (mock *dbMock) ExecContextCalls() []struct {
+	Ctx   context.Context
+	Query string
+	Args  []interface{}
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query string
+		Args  []interface{}
+	}
+	lockdbMockExecContext.RLock()
+	calls = mock.calls.ExecContext
+	lockdbMockExecContext.RUnlock()
+	return calls
+}
+
 // PingContext calls PingContextFunc.
 func (mock *dbMock) PingContext(in1 context.Context) error {
 	if mock.PingContextFunc == nil {
@@ -111,3 +177,29 @@ func (mock *dbMock) PingContextCalls() []struct {
 	lockdbMockPingContext.RUnlock()
 	return calls
 }
+
+// Stats calls StatsFunc.
+func (mock *dbMock) Stats() sql.DBStats {
+	if mock.StatsFunc == nil {
+		panic("dbMock.StatsFunc: method is nil but db.Stats was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockdbMockStats.Lock()
+	mock.calls.Stats = append(mock.calls.Stats, callInfo)
+	lockdbMockStats.Unlock()
+	return mock.StatsFunc()
+}
+
+// StatsCalls gets all the calls that were made to Stats.
+// Check the length with:
+//     len(mockeddb.StatsCalls())
+func (mock *dbMock) StatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockdbMockStats.RLock()
+	calls = mock.calls.Stats
+	lockdbMockStats.RUnlock()
+	return calls
+}
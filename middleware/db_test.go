@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/kayx-org/freja/healthcheck"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +37,9 @@ func TestNewDB(t *testing.T) {
 				PingContextFunc: func(context.Context) error {
 					return tc.pingErr
 				},
+				StatsFunc: func() sql.DBStats {
+					return sql.DBStats{}
+				},
 			}
 			midd := NewDB(db, OptionHealthCheckName(tc.hcName), OptionWindowCheck(time.Millisecond))
 			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*3)
@@ -44,8 +48,150 @@ func TestNewDB(t *testing.T) {
 			err := midd.Run(ctx)
 			time.Sleep(time.Millisecond * 6)
 			assert.NoError(t, err)
-			assert.Equal(t, tc.expectedStatus, midd.status)
+			assert.Equal(t, tc.expectedStatus, midd.Status())
 			assert.Equal(t, tc.expectedName, midd.name)
 		})
 	}
 }
+
+func TestDBStats(t *testing.T) {
+	testCases := map[string]struct {
+		waitDurations  []time.Duration
+		maxWaitGrowth  time.Duration
+		expectedStatus healthcheck.ServiceStatus
+	}{
+		"if there is no limit on the wait growth then it should be up": {
+			waitDurations:  []time.Duration{0, time.Second},
+			expectedStatus: healthcheck.UP,
+		},
+		"if the wait duration grows below the limit then it should be up": {
+			waitDurations:  []time.Duration{time.Second, time.Second + time.Millisecond},
+			maxWaitGrowth:  time.Millisecond * 10,
+			expectedStatus: healthcheck.UP,
+		},
+		"if the wait duration grows above the limit then it should be temporally unavailable": {
+			waitDurations:  []time.Duration{time.Second, time.Second * 2},
+			maxWaitGrowth:  time.Millisecond * 10,
+			expectedStatus: healthcheck.TemporallyUnavailable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			db := &dbMock{
+				PingContextFunc: func(context.Context) error {
+					return nil
+				},
+				StatsFunc: func() sql.DBStats {
+					stats := sql.DBStats{OpenConnections: 2, InUse: 1, Idle: 1, WaitDuration: tc.waitDurations[calls]}
+					calls++
+					return stats
+				},
+			}
+			midd := NewDB(db, OptionMaxWaitGrowth(tc.maxWaitGrowth))
+			for range tc.waitDurations {
+				midd.runStatusCheck(context.Background())
+			}
+
+			assert.Equal(t, tc.expectedStatus, midd.Status())
+			assert.Equal(t, tc.waitDurations[len(tc.waitDurations)-1], midd.Stats().WaitDuration)
+			assert.Equal(t, 2, midd.Details()["openConnections"])
+		})
+	}
+}
+
+func TestDBStartupPing(t *testing.T) {
+	testCases := map[string]struct {
+		failures      int
+		timeout       time.Duration
+		backoff       time.Duration
+		expectedErr   string
+		expectedCalls int
+	}{
+		"if the database is reachable it should start": {
+			timeout:       time.Second,
+			expectedCalls: 1,
+		},
+		"if the database becomes reachable it should start": {
+			failures:      2,
+			timeout:       time.Second,
+			expectedCalls: 3,
+		},
+		"if the database is not reachable before the timeout it should fail": {
+			failures:    1000,
+			timeout:     time.Millisecond * 20,
+			expectedErr: "unable to reach the database after",
+		},
+		"without backoff it should wait the minimum between the attempts": {
+			failures:      1000,
+			timeout:       time.Millisecond * 150,
+			expectedErr:   "unable to reach the database after 2 attempts",
+			expectedCalls: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			db := &dbMock{
+				PingContextFunc: func(context.Context) error {
+					calls++
+					if calls <= tc.failures {
+						return fmt.Errorf("connection refused")
+					}
+					return nil
+				},
+			}
+			midd := NewDB(db, OptionStartupPing(tc.timeout, tc.backoff))
+
+			err := midd.Init()
+
+			if tc.expectedErr != "" {
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				if tc.expectedCalls > 0 {
+					assert.Equal(t, tc.expectedCalls, calls)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
+
+func TestDBHealthQuery(t *testing.T) {
+	testCases := map[string]struct {
+		queryErr       error
+		expectedStatus healthcheck.ServiceStatus
+	}{
+		"if the health query succeeds then it should be up": {
+			expectedStatus: healthcheck.UP,
+		},
+		"if the health query fails then it should be down": {
+			queryErr:       fmt.Errorf("database is in recovery"),
+			expectedStatus: healthcheck.DOWN,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := &dbMock{
+				PingContextFunc: func(context.Context) error {
+					return nil
+				},
+				ExecContextFunc: func(context.Context, string, ...interface{}) (sql.Result, error) {
+					return nil, tc.queryErr
+				},
+				StatsFunc: func() sql.DBStats {
+					return sql.DBStats{}
+				},
+			}
+			midd := NewDB(db, OptionHealthQuery("SELECT 1"))
+			midd.runStatusCheck(context.Background())
+
+			assert.Equal(t, tc.expectedStatus, midd.Status())
+			assert.Equal(t, "SELECT 1", db.ExecContextCalls()[0].Query)
+		})
+	}
+}
@@ -11,13 +11,13 @@ import (
 type grpcMiddleware struct {
 	service  *component.GRPCServer
 	listener *net.Listener
-	status   healthcheck.ServiceStatus
+	status   *statusHolder
 }
 
 func NewGRPCMiddleware(service *component.GRPCServer) *grpcMiddleware {
 	return &grpcMiddleware{
 		service: service,
-		status:  healthcheck.UP,
+		status:  newStatusHolder(),
 	}
 }
 
@@ -33,7 +33,7 @@ func (m *grpcMiddleware) Init() error {
 
 func (m *grpcMiddleware) Run(context.Context) error {
 	defer func() {
-		m.status = healthcheck.DOWN
+		m.status.set(healthcheck.DOWN)
 	}()
 
 	if err := m.service.Server().Serve(*m.listener); err != nil {
@@ -53,5 +53,5 @@ func (m *grpcMiddleware) Name() string {
 }
 
 func (m *grpcMiddleware) Status() healthcheck.ServiceStatus {
-	return m.status
+	return m.status.get()
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate moq -out migration_mock_test.go . migrator
+type migrator interface {
+	Up(ctx context.Context) error
+}
+
+type OptionMigrationMiddleware func(*migrationMiddleware)
+
+type migrationMiddleware struct {
+	migrator migrator
+	timeout  time.Duration
+}
+
+// NewMigrationMiddleware returns a middleware that applies the pending migrations in its Init, so the service
+// doesn't start if they fail. It works with component.SQLMigrator and component.Migrator
+func NewMigrationMiddleware(m migrator, options ...OptionMigrationMiddleware) *migrationMiddleware {
+	midd := &migrationMiddleware{
+		migrator: m,
+		timeout:  time.Minute * 5,
+	}
+	for _, op := range options {
+		op(midd)
+	}
+
+	return midd
+}
+
+// OptionMigrationTimeout sets how long the migrations can take, including the wait for the lock
+func OptionMigrationTimeout(t time.Duration) OptionMigrationMiddleware {
+	return func(m *migrationMiddleware) {
+		m.timeout = t
+	}
+}
+
+func (m *migrationMiddleware) Init() error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	if err := m.migrator.Up(ctx); err != nil {
+		return fmt.Errorf("unable to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func (m *migrationMiddleware) Run(context.Context) error {
+	return nil
+}
+
+func (m *migrationMiddleware) Stop(context.Context) error {
+	return nil
+}
@@ -0,0 +1,77 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package middleware
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	lockmigratorMockUp sync.RWMutex
+)
+
+// Ensure, that migratorMock does implement migrator.
+// If this is not the case, regenerate this file with moq.
+var _ migrator = &migratorMock{}
+
+// migratorMock is a mock implementation of migrator.
+//
+//     func TestSomethingThatUsesmigrator(t *testing.T) {
+//
+//         // make and configure a mocked migrator
+//         mockedmigrator := &migratorMock{
+//             UpFunc: func(ctx context.Context) error {
+// 	               panic("mock out the Up method")
+//             },
+//         }
+//
+//         // use mockedmigrator in code that requires migrator
+//         // and then make assertions.
+//
+//     }
+type migratorMock struct {
+	// UpFunc mocks the Up method.
+	UpFunc func(ctx context.Context) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Up holds details about calls to the Up method.
+		Up []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+}
+
+// Up calls UpFunc.
+func (mock *migratorMock) Up(ctx context.Context) error {
+	if mock.UpFunc == nil {
+		panic("migratorMock.UpFunc: method is nil but migrator.Up was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockmigratorMockUp.Lock()
+	mock.calls.Up = append(mock.calls.Up, callInfo)
+	lockmigratorMockUp.Unlock()
+	return mock.UpFunc(ctx)
+}
+
+// UpCalls gets all the calls that were made to Up.
+// Check the length with:
+//     len(mockedmigrator.UpCalls())
+func (mock *migratorMock) UpCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockmigratorMockUp.RLock()
+	calls = mock.calls.Up
+	lockmigratorMockUp.RUnlock()
+	return calls
+}
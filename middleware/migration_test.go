@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/kayx-org/freja/component"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var (
+	_ migrator = &component.SQLMigrator{}
+	_ migrator = &component.Migrator{}
+)
+
+func TestMigrationMiddleware(t *testing.T) {
+	m := &migratorMock{
+		UpFunc: func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			return fmt.Errorf("test")
+		},
+	}
+	midd := NewMigrationMiddleware(m, OptionMigrationTimeout(time.Second))
+
+	assert.EqualError(t, midd.Init(), "unable to migrate: test")
+	assert.NoError(t, midd.Run(context.Background()))
+	assert.NoError(t, midd.Stop(context.Background()))
+	assert.Len(t, m.UpCalls(), 1)
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"github.com/kayx-org/freja/healthcheck"
+	"math/rand"
+	"time"
+)
+
+// CheckFunc checks the dependency, any error sets it as DOWN unless it wraps healthcheck.ErrTemporallyUnavailable
+type CheckFunc func(ctx context.Context) error
+
+type OptionPoller func(*Poller)
+
+// Poller is a Middleware which runs a CheckFunc every interval and implements the HealthChecker interface
+// with its result, it can be used to monitor any dependency (HTTP endpoint, TCP port, DNS name, etc)
+type Poller struct {
+	name     string
+	check    CheckFunc
+	interval time.Duration
+	timeout  time.Duration
+	jitter   time.Duration
+	init     func() error
+	stop     func(context.Context) error
+	status   *statusHolder
+	// notify is called with the status after every check
+	notify func(healthcheck.ServiceStatus)
+}
+
+// NewPoller returns a new Poller that runs the check every second with a timeout of the same duration,
+// it can be configured through the options
+func NewPoller(name string, check CheckFunc, options ...OptionPoller) *Poller {
+	p := &Poller{
+		name:     name,
+		check:    check,
+		interval: time.Second,
+		status:   newStatusHolder(),
+	}
+
+	for _, o := range options {
+		o(p)
+	}
+
+	return p
+}
+
+// OptionPollerInterval sets how often the check is run, if no timeout is set it'll be used as the timeout as well
+func OptionPollerInterval(t time.Duration) OptionPoller {
+	return func(p *Poller) {
+		p.interval = t
+	}
+}
+
+func OptionPollerTimeout(t time.Duration) OptionPoller {
+	return func(p *Poller) {
+		p.timeout = t
+	}
+}
+
+// OptionPollerThresholds sets the number of consecutive failed checks before the status goes DOWN
+// and the number of consecutive successful ones before it goes UP again
+func OptionPollerThresholds(failures, successes int) OptionPoller {
+	return func(p *Poller) {
+		p.status.setThresholds(failures, successes)
+	}
+}
+
+// OptionPollerJitter delays the first check by a random duration up to the given one,
+// so several replicas don't hit the dependency at the same time
+func OptionPollerJitter(t time.Duration) OptionPoller {
+	return func(p *Poller) {
+		p.jitter = t
+	}
+}
+
+// OptionPollerInit sets a function to be run during Init, if it fails the service won't start
+func OptionPollerInit(init func() error) OptionPoller {
+	return func(p *Poller) {
+		p.init = init
+	}
+}
+
+// OptionPollerStop sets a function to be run during Stop to clean up the resources
+func OptionPollerStop(stop func(context.Context) error) OptionPoller {
+	return func(p *Poller) {
+		p.stop = stop
+	}
+}
+
+func (p *Poller) Init() error {
+	if p.init != nil {
+		return p.init()
+	}
+
+	return nil
+}
+
+func (p *Poller) Run(ctx context.Context) error {
+	if p.jitter > 0 {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Duration(rand.Int63n(int64(p.jitter)))):
+		}
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.runStatusCheck(ctx)
+		}
+	}
+}
+
+func (p *Poller) Stop(ctx context.Context) error {
+	if p.stop != nil {
+		return p.stop(ctx)
+	}
+
+	return nil
+}
+
+func (p *Poller) Name() string {
+	return p.name
+}
+
+func (p *Poller) Status() healthcheck.ServiceStatus {
+	return p.status.get()
+}
+
+func (p *Poller) Details() map[string]interface{} {
+	return p.status.getDetails()
+}
+
+func (p *Poller) runStatusCheck(ctx context.Context) {
+	timeout := p.timeout
+	if timeout == 0 {
+		timeout = p.interval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	p.status.report(healthcheck.StatusFromError(p.check(ctx)))
+	if p.notify != nil {
+		p.notify(p.status.get())
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/kayx-org/freja/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPoller(t *testing.T) {
+	testCases := map[string]struct {
+		checkErr       error
+		options        []OptionPoller
+		expectedStatus healthcheck.ServiceStatus
+	}{
+		"if the check returns error then it should be down": {
+			checkErr:       fmt.Errorf("test"),
+			expectedStatus: healthcheck.DOWN,
+		},
+		"if the check is fine then it should be up": {
+			expectedStatus: healthcheck.UP,
+		},
+		"if the check wraps the temporally unavailable error then it should be unavailable": {
+			checkErr:       fmt.Errorf("slow: %w", healthcheck.ErrTemporallyUnavailable),
+			expectedStatus: healthcheck.TemporallyUnavailable,
+		},
+		"if the failures don't reach the threshold then it should be up": {
+			checkErr:       fmt.Errorf("test"),
+			options:        []OptionPoller{OptionPollerThresholds(1000, 1)},
+			expectedStatus: healthcheck.UP,
+		},
+		"if the start is jittered it should still run the check": {
+			checkErr:       fmt.Errorf("test"),
+			options:        []OptionPoller{OptionPollerJitter(time.Millisecond)},
+			expectedStatus: healthcheck.DOWN,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := append([]OptionPoller{OptionPollerInterval(time.Millisecond)}, tc.options...)
+			p := NewPoller("foo", func(context.Context) error {
+				return tc.checkErr
+			}, options...)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+			defer cancel()
+
+			err := p.Run(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, p.Status())
+			assert.Equal(t, "foo", p.Name())
+		})
+	}
+}
+
+func TestPollerTimeout(t *testing.T) {
+	p := NewPoller("foo", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, OptionPollerInterval(time.Hour), OptionPollerTimeout(time.Millisecond))
+
+	p.runStatusCheck(context.Background())
+	assert.Equal(t, healthcheck.DOWN, p.Status())
+}
+
+func TestPollerInitAndStop(t *testing.T) {
+	var initCalls, stopCalls int
+	p := NewPoller("foo", func(context.Context) error { return nil },
+		OptionPollerInit(func() error {
+			initCalls++
+			return fmt.Errorf("test")
+		}),
+		OptionPollerStop(func(context.Context) error {
+			stopCalls++
+			return nil
+		}),
+	)
+
+	assert.EqualError(t, p.Init(), "test")
+	assert.NoError(t, p.Stop(context.Background()))
+	assert.Equal(t, 1, initCalls)
+	assert.Equal(t, 1, stopCalls)
+}
@@ -1,69 +1,174 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
+	"fmt"
 	"github.com/go-redis/redis/v8"
 	"github.com/kayx-org/freja/healthcheck"
+	"strings"
 	"time"
 )
 
+//go:generate moq -out redis_mock_test.go . redisClient
 type redisClient interface {
 	Ping(ctx context.Context) *redis.StatusCmd
+	Info(ctx context.Context, section ...string) *redis.StringCmd
+	PoolStats() *redis.PoolStats
 	Close() error
 }
 
+// infoKeys are the keys taken from the INFO command, grouped by section, to be reported in the health-check details
+var infoKeys = map[string][]string{
+	"memory":      {"used_memory", "used_memory_human", "used_memory_peak", "maxmemory", "mem_fragmentation_ratio"},
+	"replication": {"role", "connected_slaves", "master_link_status", "master_last_io_seconds_ago"},
+}
+
+type OptionRedisMiddleware func(*redisMiddleware)
+
 type redisMiddleware struct {
-	client      redisClient
-	name        string
-	checkWindow time.Duration
-	status      healthcheck.ServiceStatus
+	*Poller
+	client          redisClient
+	maxLatency      time.Duration
+	maxPoolTimeouts uint32
+	infoSections    []string
+	lastTimeouts    uint32
 }
 
-func NewRedisMiddleware(client redisClient) *redisMiddleware {
-	return &redisMiddleware{
-		client:      client,
-		name:        "redis",
-		checkWindow: time.Second,
-		status:      healthcheck.UP,
+// NewRedisMiddleware returns a new Redis middleware which also implements the HealthCheck interface,
+// besides the status it reports the ping latency, the pool stats and, if configured, data from the INFO command
+func NewRedisMiddleware(client redisClient, options ...OptionRedisMiddleware) *redisMiddleware {
+	m := &redisMiddleware{client: client}
+	m.Poller = NewPoller("redis", m.check)
+
+	for _, op := range options {
+		op(m)
 	}
+
+	return m
 }
 
-func (m *redisMiddleware) Init() error {
-	_, err := m.client.Ping(context.Background()).Result()
-	return err
+func OptionRedisWindowCheck(t time.Duration) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.interval = t
+	}
 }
 
-func (m *redisMiddleware) Run(ctx context.Context) error {
-	ticker := time.NewTicker(m.checkWindow)
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			m.runStatusCheck(ctx)
-		}
+func OptionRedisHealthCheckName(name string) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.name = name
+	}
+}
+
+// OptionRedisMaxLatency sets the ping latency above which the status becomes TemporallyUnavailable
+func OptionRedisMaxLatency(t time.Duration) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.maxLatency = t
+	}
+}
+
+// OptionRedisMaxPoolTimeouts sets the number of pool timeouts, between two checks, above which
+// the status becomes TemporallyUnavailable
+func OptionRedisMaxPoolTimeouts(timeouts uint32) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.maxPoolTimeouts = timeouts
+	}
+}
+
+// OptionRedisInfo adds the memory and/or replication data from the INFO command to the health-check details
+func OptionRedisInfo(sections ...string) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.infoSections = sections
+	}
+}
+
+// OptionRedisThresholds sets the number of consecutive failed checks before the status goes DOWN
+// and the number of consecutive successful ones before it goes UP again
+func OptionRedisThresholds(failures, successes int) OptionRedisMiddleware {
+	return func(m *redisMiddleware) {
+		m.status.setThresholds(failures, successes)
 	}
 }
 
+func (m *redisMiddleware) Init() error {
+	_, err := m.client.Ping(context.Background()).Result()
+	return err
+}
+
 func (m *redisMiddleware) Stop(context.Context) error {
 	return m.client.Close()
 }
 
-func (m *redisMiddleware) Name() string {
-	return m.name
-}
+func (m *redisMiddleware) check(ctx context.Context) error {
+	details := map[string]interface{}{}
+	start := time.Now()
+	_, err := m.client.Ping(ctx).Result()
+	latency := time.Since(start)
+	details["latencyMs"] = latency.Milliseconds()
+
+	stats := m.client.PoolStats()
+	timeouts := stats.Timeouts - m.lastTimeouts
+	m.lastTimeouts = stats.Timeouts
+	details["pool"] = map[string]interface{}{
+		"hits":       stats.Hits,
+		"misses":     stats.Misses,
+		"timeouts":   stats.Timeouts,
+		"totalConns": stats.TotalConns,
+		"idleConns":  stats.IdleConns,
+		"staleConns": stats.StaleConns,
+	}
 
-func (m *redisMiddleware) runStatusCheck(ctx context.Context) {
-	ctx, cancel := context.WithTimeout(ctx, m.checkWindow)
-	defer cancel()
+	if err == nil {
+		for _, section := range m.infoSections {
+			info, err := m.client.Info(ctx, section).Result()
+			if err != nil {
+				details[section] = err.Error()
+				continue
+			}
+			details[section] = parseInfo(info, infoKeys[section])
+		}
+	}
 
-	if _, err := m.client.Ping(ctx).Result(); err != nil {
-		m.status = healthcheck.DOWN
-	} else {
-		m.status = healthcheck.UP
+	m.status.setDetails(details)
+	switch {
+	case err != nil:
+		return err
+	case m.maxLatency > 0 && latency > m.maxLatency:
+		return fmt.Errorf("latency of %s above %s: %w", latency, m.maxLatency, healthcheck.ErrTemporallyUnavailable)
+	case m.maxPoolTimeouts > 0 && timeouts > m.maxPoolTimeouts:
+		return fmt.Errorf("%d pool timeouts above %d: %w", timeouts, m.maxPoolTimeouts, healthcheck.ErrTemporallyUnavailable)
+	default:
+		return nil
 	}
 }
 
-func (m *redisMiddleware) Status() healthcheck.ServiceStatus {
-	return m.status
+// parseInfo reads the `key:value` lines returned by the INFO command, if keys is empty all of them are returned
+func parseInfo(info string, keys []string) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		values[kv[0]] = kv[1]
+	}
+
+	if len(keys) == 0 {
+		return values
+	}
+
+	filtered := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
 }
@@ -0,0 +1,199 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package middleware
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"sync"
+)
+
+var (
+	lockredisClientMockClose     sync.RWMutex
+	lockredisClientMockInfo      sync.RWMutex
+	lockredisClientMockPing      sync.RWMutex
+	lockredisClientMockPoolStats sync.RWMutex
+)
+
+// Ensure, that redisClientMock does implement redisClient.
+// If this is not the case, regenerate this file with moq.
+var _ redisClient = &redisClientMock{}
+
+// redisClientMock is a mock implementation of redisClient.
+//
+//     func TestSomethingThatUsesredisClient(t *testing.T) {
+//
+//         // make and configure a mocked redisClient
+//         mockedredisClient := &redisClientMock{
+//             CloseFunc: func() error {
+// 	               panic("mock out the Close method")
+//             },
+//             InfoFunc: func(ctx context.Context, section ...string) *redis.StringCmd {
+// 	               panic("mock out the Info method")
+//             },
+//             PingFunc: func(ctx context.Context) *redis.StatusCmd {
+// 	               panic("mock out the Ping method")
+//             },
+//             PoolStatsFunc: func() *redis.PoolStats {
+// 	               panic("mock out the PoolStats method")
+//             },
+//         }
+//
+//         // use mockedredisClient in code that requires redisClient
+//         // and then make assertions.
+//
+//     }
+type redisClientMock struct {
+	// CloseFunc mocks the Close method.
+	CloseFunc func() error
+
+	// InfoFunc mocks the Info method.
+	InfoFunc func(ctx context.Context, section ...string) *redis.StringCmd
+
+	// PingFunc mocks the Ping method.
+	PingFunc func(ctx context.Context) *redis.StatusCmd
+
+	// PoolStatsFunc mocks the PoolStats method.
+	PoolStatsFunc func() *redis.PoolStats
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Close holds details about calls to the Close method.
+		Close []struct {
+		}
+		// Info holds details about calls to the Info method.
+		Info []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Section is the section argument value.
+			Section []string
+		}
+		// Ping holds details about calls to the Ping method.
+		Ping []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// PoolStats holds details about calls to the PoolStats method.
+		PoolStats []struct {
+		}
+	}
+}
+
+// Close calls CloseFunc.
+func (mock *redisClientMock) Close() error {
+	if mock.CloseFunc == nil {
+		panic("redisClientMock.CloseFunc: method is nil but redisClient.Close was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockredisClientMockClose.Lock()
+	mock.calls.Close = append(mock.calls.Close, callInfo)
+	lockredisClientMockClose.Unlock()
+	return mock.CloseFunc()
+}
+
+// CloseCalls gets all the calls that were made to Close.
+// Check the length with:
+//     len(mockedredisClient.CloseCalls())
+func (mock *redisClientMock) CloseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockredisClientMockClose.RLock()
+	calls = mock.calls.Close
+	lockredisClientMockClose.RUnlock()
+	return calls
+}
+
+// Info calls InfoFunc.
+func (mock *redisClientMock) Info(ctx context.Context, section ...string) *redis.StringCmd {
+	if mock.InfoFunc == nil {
+		panic("redisClientMock.InfoFunc: method is nil but redisClient.Info was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Section []string
+	}{
+		Ctx:     ctx,
+		Section: section,
+	}
+	lockredisClientMockInfo.Lock()
+	mock.calls.Info = append(mock.calls.Info, callInfo)
+	lockredisClientMockInfo.Unlock()
+	return mock.InfoFunc(ctx, section...)
+}
+
+// InfoCalls gets all the calls that were made to Info.
+// Check the length with:
+//     len(mockedredisClient.InfoCalls())
+func (mock *redisClientMock) InfoCalls() []struct {
+	Ctx     context.Context
+	Section []string
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Section []string
+	}
+	lockredisClientMockInfo.RLock()
+	calls = mock.calls.Info
+	lockredisClientMockInfo.RUnlock()
+	return calls
+}
+
+// Ping calls PingFunc.
+func (mock *redisClientMock) Ping(ctx context.Context) *redis.StatusCmd {
+	if mock.PingFunc == nil {
+		panic("redisClientMock.PingFunc: method is nil but redisClient.Ping was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	lockredisClientMockPing.Lock()
+	mock.calls.Ping = append(mock.calls.Ping, callInfo)
+	lockredisClientMockPing.Unlock()
+	return mock.PingFunc(ctx)
+}
+
+// PingCalls gets all the calls that were made to Ping.
+// Check the length with:
+//     len(mockedredisClient.PingCalls())
+func (mock *redisClientMock) PingCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	lockredisClientMockPing.RLock()
+	calls = mock.calls.Ping
+	lockredisClientMockPing.RUnlock()
+	return calls
+}
+
+// PoolStats calls PoolStatsFunc.
+func (mock *redisClientMock) PoolStats() *redis.PoolStats {
+	if mock.PoolStatsFunc == nil {
+		panic("redisClientMock.PoolStatsFunc: method is nil but redisClient.PoolStats was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockredisClientMockPoolStats.Lock()
+	mock.calls.PoolStats = append(mock.calls.PoolStats, callInfo)
+	lockredisClientMockPoolStats.Unlock()
+	return mock.PoolStatsFunc()
+}
+
+// PoolStatsCalls gets all the calls that were made to PoolStats.
+// Check the length with:
+//     len(mockedredisClient.PoolStatsCalls())
+func (mock *redisClientMock) PoolStatsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockredisClientMockPoolStats.RLock()
+	calls = mock.calls.PoolStats
+	lockredisClientMockPoolStats.RUnlock()
+	return calls
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/kayx-org/freja/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedisMiddlewareStatusCheck(t *testing.T) {
+	testCases := map[string]struct {
+		pingErr         error
+		pingDelay       time.Duration
+		timeouts        uint32
+		options         []OptionRedisMiddleware
+		expectedStatus  healthcheck.ServiceStatus
+		expectedDetails []string
+	}{
+		"if the ping returns error then it should be down": {
+			pingErr:         fmt.Errorf("test"),
+			expectedStatus:  healthcheck.DOWN,
+			expectedDetails: []string{"latencyMs", "pool"},
+		},
+		"if the ping is fine then it should be up": {
+			expectedStatus:  healthcheck.UP,
+			expectedDetails: []string{"latencyMs", "pool"},
+		},
+		"if the latency is above the limit then it should be temporally unavailable": {
+			pingDelay:       time.Millisecond * 5,
+			options:         []OptionRedisMiddleware{OptionRedisMaxLatency(time.Millisecond)},
+			expectedStatus:  healthcheck.TemporallyUnavailable,
+			expectedDetails: []string{"latencyMs", "pool"},
+		},
+		"if the pool timeouts are above the limit then it should be temporally unavailable": {
+			timeouts:        3,
+			options:         []OptionRedisMiddleware{OptionRedisMaxPoolTimeouts(2)},
+			expectedStatus:  healthcheck.TemporallyUnavailable,
+			expectedDetails: []string{"latencyMs", "pool"},
+		},
+		"if the info sections are configured they should be reported": {
+			options:         []OptionRedisMiddleware{OptionRedisInfo("memory", "replication")},
+			expectedStatus:  healthcheck.UP,
+			expectedDetails: []string{"latencyMs", "pool", "memory", "replication"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := &redisClientMock{
+				PingFunc: func(ctx context.Context) *redis.StatusCmd {
+					time.Sleep(tc.pingDelay)
+					return redis.NewStatusResult("PONG", tc.pingErr)
+				},
+				PoolStatsFunc: func() *redis.PoolStats {
+					return &redis.PoolStats{Hits: 1, Timeouts: tc.timeouts, TotalConns: 2, IdleConns: 1}
+				},
+				InfoFunc: func(ctx context.Context, section ...string) *redis.StringCmd {
+					return redis.NewStringResult("# Memory\r\nused_memory:1024\r\n# Replication\r\nrole:master\r\n", nil)
+				},
+			}
+			midd := NewRedisMiddleware(client, tc.options...)
+			midd.runStatusCheck(context.Background())
+
+			assert.Equal(t, tc.expectedStatus, midd.Status())
+			assert.Len(t, midd.Details(), len(tc.expectedDetails))
+			for _, k := range tc.expectedDetails {
+				assert.Contains(t, midd.Details(), k)
+			}
+		})
+	}
+}
+
+func TestParseInfo(t *testing.T) {
+	info := "# Memory\r\nused_memory:1024\r\nused_memory_human:1K\r\n\r\n# Replication\r\nrole:master\r\n"
+
+	assert.Equal(t, map[string]string{"used_memory": "1024", "role": "master"},
+		parseInfo(info, []string{"used_memory", "role", "missing"}))
+	assert.Len(t, parseInfo(info, nil), 3)
+}
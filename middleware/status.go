@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"github.com/kayx-org/freja/healthcheck"
+	"sync"
+)
+
+// statusHolder keeps the status of a middleware safe to be written from the Run goroutine and read from the
+// health-check handlers. The status only goes DOWN after failureThreshold consecutive failures and it only
+// recovers after successThreshold consecutive successes, so single blips don't flip the readiness of the service
+type statusHolder struct {
+	mu               sync.RWMutex
+	status           healthcheck.ServiceStatus
+	details          map[string]interface{}
+	failureThreshold int
+	successThreshold int
+	failures         int
+	successes        int
+}
+
+func newStatusHolder() *statusHolder {
+	return &statusHolder{
+		status:           healthcheck.UP,
+		failureThreshold: 1,
+		successThreshold: 1,
+	}
+}
+
+func (h *statusHolder) setThresholds(failureThreshold, successThreshold int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if failureThreshold > 0 {
+		h.failureThreshold = failureThreshold
+	}
+	if successThreshold > 0 {
+		h.successThreshold = successThreshold
+	}
+}
+
+// report registers the result of a check, DOWN counts as a failure, anything else as a success
+func (h *statusHolder) report(status healthcheck.ServiceStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if status.IsDown() {
+		h.successes = 0
+		h.failures++
+		if h.failures >= h.failureThreshold {
+			h.status = healthcheck.DOWN
+		}
+		return
+	}
+
+	h.failures = 0
+	h.successes++
+	if h.status.IsDown() && h.successes < h.successThreshold {
+		return
+	}
+	h.status = status
+}
+
+// set forces the status without taking into account the thresholds
+func (h *statusHolder) set(status healthcheck.ServiceStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.status = status
+	h.failures = 0
+	h.successes = 0
+}
+
+func (h *statusHolder) get() healthcheck.ServiceStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.status
+}
+
+func (h *statusHolder) setDetails(details map[string]interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.details = details
+}
+
+// getDetails returns a copy of the details, so they can be encoded while the next check is running
+func (h *statusHolder) getDetails() map[string]interface{} {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	details := make(map[string]interface{}, len(h.details))
+	for k, v := range h.details {
+		details[k] = v
+	}
+
+	return details
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"github.com/kayx-org/freja/healthcheck"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStatusHolder(t *testing.T) {
+	up, down, unavailable := healthcheck.UP, healthcheck.DOWN, healthcheck.TemporallyUnavailable
+	testCases := map[string]struct {
+		failureThreshold int
+		successThreshold int
+		reports          []healthcheck.ServiceStatus
+		expectedStatus   healthcheck.ServiceStatus
+	}{
+		"without thresholds a single failure should set it down": {
+			reports:        []healthcheck.ServiceStatus{up, down},
+			expectedStatus: down,
+		},
+		"without thresholds a single success should set it up": {
+			reports:        []healthcheck.ServiceStatus{down, up},
+			expectedStatus: up,
+		},
+		"if the failures don't reach the threshold it should stay up": {
+			failureThreshold: 3,
+			reports:          []healthcheck.ServiceStatus{down, down, up, down, down},
+			expectedStatus:   up,
+		},
+		"if the failures reach the threshold it should be down": {
+			failureThreshold: 3,
+			reports:          []healthcheck.ServiceStatus{down, down, down},
+			expectedStatus:   down,
+		},
+		"if the successes don't reach the threshold it should stay down": {
+			successThreshold: 2,
+			reports:          []healthcheck.ServiceStatus{down, up, down, up},
+			expectedStatus:   down,
+		},
+		"if the successes reach the threshold it should be up": {
+			successThreshold: 2,
+			reports:          []healthcheck.ServiceStatus{down, up, up},
+			expectedStatus:   up,
+		},
+		"temporally unavailable should count as a success": {
+			failureThreshold: 2,
+			reports:          []healthcheck.ServiceStatus{down, unavailable, down},
+			expectedStatus:   unavailable,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := newStatusHolder()
+			h.setThresholds(tc.failureThreshold, tc.successThreshold)
+			for _, s := range tc.reports {
+				h.report(s)
+			}
+
+			assert.Equal(t, tc.expectedStatus, h.get())
+		})
+	}
+}